@@ -18,13 +18,74 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
-// +kubebuilder:object:root=true
+const ManagedKind Kind = "Managed"
+
+// ManagedSpec defines the desired state of Managed. Exactly one of SampleRef and Resources is set.
+// +kubebuilder:validation:XValidation:rule="has(self.sampleRef) != has(self.resources)",message="exactly one of sampleRef and resources must be set"
+type ManagedSpec struct {
+	// SampleRef references a Sample in the namespace of the Managed.
+	// The Managed reflects the state of the Sample and is garbage collected together with it.
+	// +optional
+	SampleRef *SampleReference `json:"sampleRef,omitempty"`
+
+	// Resources are namespaced objects applied to the namespace of the Managed.
+	// They are owned by the Managed and garbage collected together with it.
+	// +optional
+	// +kubebuilder:validation:MinItems=1
+	Resources []runtime.RawExtension `json:"resources,omitempty"`
+}
+
+// SampleReference references a Sample in the namespace of the referencing object.
+type SampleReference struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+type ManagedStatus struct {
+	Status `json:",inline"`
+
+	// Conditions contain a set of conditionals to determine the State of Status.
+	// If all Conditions are met, State is expected to be in StateReady.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Inventory lists the objects applied from Resources, so that they are pruned once removed.
+	// +optional
+	Inventory []InventoryEntry `json:"inventory,omitempty"`
+}
+
+func (s *ManagedStatus) WithState(state State) *ManagedStatus {
+	s.State = state
+	return s
+}
+
+func (s *ManagedStatus) WithInventory(inventory []InventoryEntry) *ManagedStatus {
+	s.Inventory = inventory
+	return s
+}
 
+// WithInstalledCondition sets the Installed condition.
+func (s *ManagedStatus) WithInstalledCondition(status metav1.ConditionStatus, reason, message string,
+	objGeneration int64,
+) *ManagedStatus {
+	setCondition(&s.Conditions, ConditionTypeInstalled, status, reason, message, objGeneration)
+	return s
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="State",type=string,JSONPath=".status.state"
+
+// Managed is the Schema for the manageds API. It is either a dependent of a Sample,
+// or the owner of a set of inline objects.
 type Managed struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ManagedSpec   `json:"spec,omitempty"`
+	Status ManagedStatus `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,4 @@
+package v1alpha1
+
+// Hub marks this type as the conversion hub, which all other versions of the Sample are converted from and to.
+func (*Sample) Hub() {}
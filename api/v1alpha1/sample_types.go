@@ -24,6 +24,7 @@ package v1alpha1
 import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
@@ -38,8 +39,72 @@ var (
 	// GroupVersion is group version used to register these objects.
 	GroupVersion = schema.GroupVersion{Group: "operator.kyma-project.io", Version: "v1alpha1"}
 
+	// ConditionTypeInstallation mirrors the Installed condition for clients relying on its former name.
 	ConditionTypeInstallation = "Installation"
-	ConditionReasonReady      = "Ready"
+	// ConditionTypeInstalled is True once the objects of the manifest are applied and healthy.
+	ConditionTypeInstalled = "Installed"
+	// ConditionTypeProgressing is True while the objects of the manifest are applied and rolled out.
+	ConditionTypeProgressing = "Progressing"
+	// ConditionTypeDegraded is True while the installation failed or objects of the manifest are degraded.
+	ConditionTypeDegraded = "Degraded"
+	// ConditionTypeDeleting is True once the Sample is marked for deletion.
+	ConditionTypeDeleting = "Deleting"
+	ConditionTypeHealthy  = "Healthy"
+	ConditionTypeDrifted  = "Drifted"
+	// ConditionTypeSuspended is True while the reconciliation of the Sample is suspended.
+	ConditionTypeSuspended = "Suspended"
+
+	ConditionReasonReady               = "Ready"
+	ConditionReasonInstalling          = "Installing"
+	ConditionReasonWaitingForResources = "WaitingForResources"
+	ConditionReasonDryRun              = "DryRun"
+	ConditionReasonManifestNotFound    = "ManifestNotFound"
+	ConditionReasonManifestInvalid     = "ManifestInvalid"
+	ConditionReasonApplyFailed         = "ApplyFailed"
+	ConditionReasonHealthCheckFailed   = "HealthCheckFailed"
+	ConditionReasonHealthCheckTimeout  = "HealthCheckTimeout"
+	ConditionReasonResourcesDegraded   = "ResourcesDegraded"
+	ConditionReasonPruneFailed         = "PruneFailed"
+	ConditionReasonDeleting            = "Deleting"
+	ConditionReasonDeletionFailed      = "DeletionFailed"
+	ConditionReasonDeletionBlocked     = "DeletionBlocked"
+	ConditionReasonSampleNotFound      = "SampleNotFound"
+	ConditionReasonSuspended           = "Suspended"
+	ConditionReasonResumed             = "Resumed"
+
+	// ConditionTypeScenario is True while the State of the Sample is scripted by its Scenario.
+	ConditionTypeScenario            = "Scenario"
+	ConditionReasonScenarioRunning   = "ScenarioRunning"
+	ConditionReasonScenarioCompleted = "ScenarioCompleted"
+	ConditionReasonScenarioInvalid   = "ScenarioInvalid"
+
+	// ConditionTypeAdopted is True once the objects selected by a ThirdParty are adopted by the Sample.
+	ConditionTypeAdopted            = "Adopted"
+	ConditionReasonObserved         = "Observed"
+	ConditionReasonAdopted          = "Adopted"
+	ConditionReasonOwnershipTaken   = "OwnershipTaken"
+	ConditionReasonAdoptionFailed   = "AdoptionFailed"
+	ConditionReasonSelectionInvalid = "SelectionInvalid"
+
+	ConditionReasonNoDrift         = "NoDrift"
+	ConditionReasonDriftDetected   = "DriftDetected"
+	ConditionReasonDriftRemediated = "DriftRemediated"
+
+	ConditionTypeManifestInvalid    = "ManifestInvalid"
+	ConditionReasonManifestValid    = "ManifestValid"
+	ConditionReasonInvalidDocuments = "InvalidDocuments"
+)
+
+// Health describes the health of an object applied from the manifest of a Sample.
+type Health string
+
+const (
+	// HealthHealthy signifies the object is fully rolled out and available.
+	HealthHealthy Health = "Healthy"
+	// HealthProgressing signifies the object is still being rolled out.
+	HealthProgressing Health = "Progressing"
+	// HealthDegraded signifies the object failed and is not expected to recover without user interaction.
+	HealthDegraded Health = "Degraded"
 )
 
 type SampleStatus struct {
@@ -48,6 +113,130 @@ type SampleStatus struct {
 	// Conditions contain a set of conditionals to determine the State of Status.
 	// If all Conditions are met, State is expected to be in StateReady.
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Inventory lists the objects applied from the manifest of the Sample.
+	// Objects which drop out of the manifest are pruned based on it,
+	// and it determines the objects that are removed when the Sample is deleted.
+	// +optional
+	Inventory []InventoryEntry `json:"inventory,omitempty"`
+
+	// AdoptedInventory lists the objects of other tools adopted by the Sample through ThirdParties.
+	// They are never pruned, but they are removed when the Sample is deleted.
+	// +optional
+	AdoptedInventory []InventoryEntry `json:"adoptedInventory,omitempty"`
+
+	// CurrentWave is the apply wave of the manifest objects that was applied last.
+	// Objects of later waves are applied once all objects of the current wave are healthy.
+	// +optional
+	CurrentWave *int32 `json:"currentWave,omitempty"`
+
+	// Plan lists the changes applying the manifest would cause. It is only recorded in dry-run mode.
+	// +optional
+	Plan *Plan `json:"plan,omitempty"`
+
+	// Resources reports the status of each object of the manifest, in apply order.
+	// The list is capped to a configurable size, in which case objects which are not healthy are kept.
+	// +optional
+	Resources []ResourceStatus `json:"resources,omitempty"`
+
+	// OmittedResources is the number of objects of the manifest which are not listed in Resources.
+	// +optional
+	OmittedResources int32 `json:"omittedResources,omitempty"`
+
+	// GenerationObservedTime is the time the objects of the current generation of the Sample were first applied.
+	// The HealthCheckTimeout is measured from it, or from the time the objects became unhealthy, if that is later.
+	// +optional
+	GenerationObservedTime *metav1.Time `json:"generationObservedTime,omitempty"`
+
+	// Scenario is the progress of the Scenario of the Sample, if one is set.
+	// +optional
+	Scenario *ScenarioStatus `json:"scenario,omitempty"`
+}
+
+// ScenarioStatus is the progress of the Scenario of a Sample.
+type ScenarioStatus struct {
+	// Step is the index of the current step of the Scenario.
+	Step int32 `json:"step"`
+
+	// StepStartTime is the time the current step started.
+	StepStartTime metav1.Time `json:"stepStartTime"`
+
+	// Reconciles is the number of reconciliations of the Sample during the current step.
+	// +optional
+	Reconciles int32 `json:"reconciles,omitempty"`
+
+	// LastReconcileTime is the time the Sample was last reconciled during the Scenario.
+	// +optional
+	LastReconcileTime metav1.Time `json:"lastReconcileTime,omitempty"`
+
+	// ObservedGeneration is the generation of the Sample the Scenario was started for.
+	// The Scenario is started from its first step again whenever the spec of the Sample changes.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// ResourceStatus is the status of a single object of the manifest of a Sample.
+type ResourceStatus struct {
+	InventoryEntry `json:",inline"`
+
+	// AppliedGeneration is the generation of the object observed after it was last applied.
+	// +optional
+	AppliedGeneration int64 `json:"appliedGeneration,omitempty"`
+
+	// Health of the object, empty if the object was not applied yet.
+	// +optional
+	Health Health `json:"health,omitempty"`
+
+	// Message explains the health of the object.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LastError is the error which occurred when the object was last applied.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+}
+
+// Plan describes the changes applying the manifest of a Sample, or deleting it, would cause.
+type Plan struct {
+	// Summary counts the planned changes, e.g. "1 to create, 2 to change, 0 to prune, 3 unchanged".
+	Summary string `json:"summary"`
+
+	// Changes lists the objects which would be created, changed, pruned, deleted or retained.
+	// +optional
+	Changes []PlannedChange `json:"changes,omitempty"`
+}
+
+// PlanAction is the change planned for an object.
+type PlanAction string
+
+const (
+	PlanActionCreate PlanAction = "Create"
+	PlanActionChange PlanAction = "Change"
+	PlanActionPrune  PlanAction = "Prune"
+	// PlanActionDelete and PlanActionRetain are planned for the objects of a Sample deleted in dry-run mode.
+	PlanActionDelete PlanAction = "Delete"
+	PlanActionRetain PlanAction = "Retain"
+)
+
+// PlannedChange is the change planned for a single object.
+type PlannedChange struct {
+	Action         PlanAction `json:"action"`
+	InventoryEntry `json:",inline"`
+
+	// Fields are the paths of the fields which would change.
+	// +optional
+	Fields []string `json:"fields,omitempty"`
+}
+
+// InventoryEntry identifies an object applied from the manifest of a Sample.
+type InventoryEntry struct {
+	// +optional
+	Group   string `json:"group,omitempty"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
 }
 
 func (s *SampleStatus) WithState(state State) *SampleStatus {
@@ -55,35 +244,343 @@ func (s *SampleStatus) WithState(state State) *SampleStatus {
 	return s
 }
 
-func (s *SampleStatus) WithInstallConditionStatus(status metav1.ConditionStatus, objGeneration int64) *SampleStatus {
-	if s.Conditions == nil {
-		s.Conditions = make([]metav1.Condition, 0, 1)
+func (s *SampleStatus) WithInventory(inventory []InventoryEntry) *SampleStatus {
+	s.Inventory = inventory
+	return s
+}
+
+func (s *SampleStatus) WithAdoptedInventory(inventory []InventoryEntry) *SampleStatus {
+	s.AdoptedInventory = inventory
+	return s
+}
+
+func (s *SampleStatus) WithResources(resources []ResourceStatus, omitted int32) *SampleStatus {
+	s.Resources = resources
+	s.OmittedResources = omitted
+	return s
+}
+
+func (s *SampleStatus) WithPlan(plan *Plan) *SampleStatus {
+	s.Plan = plan
+	return s
+}
+
+func (s *SampleStatus) WithCurrentWave(wave int32) *SampleStatus {
+	s.CurrentWave = &wave
+	return s
+}
+
+// WithHealthCondition sets the Healthy condition reflecting the aggregated health of the applied objects.
+// The time the objGeneration is first observed is recorded in GenerationObservedTime.
+func (s *SampleStatus) WithHealthCondition(health Health, message string, objGeneration int64) *SampleStatus {
+	status := metav1.ConditionFalse
+	if health == HealthHealthy {
+		status = metav1.ConditionTrue
 	}
+	if condition := meta.FindStatusCondition(s.Conditions, ConditionTypeHealthy); condition == nil ||
+		condition.ObservedGeneration != objGeneration {
+		now := metav1.Now()
+		s.GenerationObservedTime = &now
+	}
+	return s.WithCondition(ConditionTypeHealthy, status, string(health), message, objGeneration)
+}
 
-	condition := meta.FindStatusCondition(s.Conditions, ConditionTypeInstallation)
+// WithDriftCondition sets the Drifted condition, which is True while drifted objects are left in place.
+func (s *SampleStatus) WithDriftCondition(status metav1.ConditionStatus, reason, message string,
+	objGeneration int64,
+) *SampleStatus {
+	return s.WithCondition(ConditionTypeDrifted, status, reason, message, objGeneration)
+}
 
-	if condition == nil {
-		condition = &metav1.Condition{
-			Type:    ConditionTypeInstallation,
-			Reason:  ConditionReasonReady,
-			Message: "installation is ready and resources can be used",
-		}
+// WithManifestInvalidCondition sets the ManifestInvalid condition, listing the invalid documents in the message.
+func (s *SampleStatus) WithManifestInvalidCondition(invalid bool, message string, objGeneration int64) *SampleStatus {
+	status, reason := metav1.ConditionFalse, ConditionReasonManifestValid
+	if invalid {
+		status, reason = metav1.ConditionTrue, ConditionReasonInvalidDocuments
 	}
+	return s.WithCondition(ConditionTypeManifestInvalid, status, reason, message, objGeneration)
+}
 
-	condition.Status = status
-	condition.ObservedGeneration = objGeneration
-	meta.SetStatusCondition(&s.Conditions, *condition)
+// WithInstalledCondition sets the Installed condition and the Installation condition mirroring it.
+func (s *SampleStatus) WithInstalledCondition(status metav1.ConditionStatus, reason, message string,
+	objGeneration int64,
+) *SampleStatus {
+	return s.WithCondition(ConditionTypeInstalled, status, reason, message, objGeneration).
+		WithCondition(ConditionTypeInstallation, status, reason, message, objGeneration)
+}
+
+// WithInstallConditionStatus sets the status of the Installed and Installation conditions,
+// keeping the reason and message of the Installation condition if it is already set.
+//
+// Deprecated: use WithInstalledCondition, which sets the reason and message as well.
+func (s *SampleStatus) WithInstallConditionStatus(status metav1.ConditionStatus, objGeneration int64) *SampleStatus {
+	reason, message := ConditionReasonReady, "installation is ready and resources can be used"
+	if condition := meta.FindStatusCondition(s.Conditions, ConditionTypeInstallation); condition != nil {
+		reason, message = condition.Reason, condition.Message
+	}
+	return s.WithInstalledCondition(status, reason, message, objGeneration)
+}
+
+// WithDeletingCondition sets the Deleting condition to True, with the reason the deletion is at.
+func (s *SampleStatus) WithDeletingCondition(reason, message string, objGeneration int64) *SampleStatus {
+	return s.WithCondition(ConditionTypeDeleting, metav1.ConditionTrue, reason, message, objGeneration)
+}
+
+// WithCondition sets the condition of the conditionType.
+func (s *SampleStatus) WithCondition(conditionType string, status metav1.ConditionStatus, reason, message string,
+	objGeneration int64,
+) *SampleStatus {
+	setCondition(&s.Conditions, conditionType, status, reason, message, objGeneration)
 	return s
 }
 
 type SampleSpec struct {
-	// ResourceFilePath indicates the local dir path containing a .yaml or .yml,
-	// with all required resources to be processed
+	// ResourceFilePath indicates the local dir path containing .yaml or .yml files,
+	// with all required resources to be processed.
+	// Nested directories are walked recursively and the files are loaded in lexical order
+	// of their path relative to ResourceFilePath. A path to a single file is also accepted.
 	ResourceFilePath string `json:"resourceFilePath,omitempty"`
+
+	// IncludePatterns restricts the loaded .yaml and .yml files to those matching at least one of the
+	// glob patterns. Patterns containing a "/" are matched against the path relative to ResourceFilePath,
+	// all other patterns are matched against the file name. If empty, all files are included.
+	// +optional
+	IncludePatterns []string `json:"includePatterns,omitempty"`
+
+	// ExcludePatterns skips files matching any of the glob patterns, following the same matching
+	// rules as IncludePatterns. Exclusion takes precedence over inclusion.
+	// +optional
+	ExcludePatterns []string `json:"excludePatterns,omitempty"`
+
+	// Helm references a Helm chart that is rendered in-process instead of loading pre-rendered
+	// manifests from ResourceFilePath. The release name and namespace are taken from the Sample.
+	// +optional
+	Helm *HelmSource `json:"helm,omitempty"`
+
+	// Kustomize references a kustomization directory that is built in-process instead of loading
+	// pre-rendered manifests from ResourceFilePath.
+	// +optional
+	Kustomize *KustomizeSource `json:"kustomize,omitempty"`
+
+	// HealthChecks define the status conditions indicating the health of kinds
+	// without a built-in health evaluation.
+	// +optional
+	HealthChecks []CustomHealthCheck `json:"healthChecks,omitempty"`
+
+	// DriftPolicy determines how changes of the applied objects made outside of the operator are handled.
+	// Remediate reverts them, Report only reports them in the Drifted condition and leaves the objects untouched.
+	// +kubebuilder:validation:Enum=Remediate;Report
+	// +kubebuilder:default=Remediate
+	// +optional
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+
+	// DeletionPolicy determines what happens to the objects of the manifest when the Sample is deleted.
+	// Delete removes them, Orphan leaves them untouched, and Retain leaves them stripped of the operator labels.
+	// The annotation operator.kyma-project.io/deletion-policy overrides it for single objects.
+	// +kubebuilder:validation:Enum=Delete;Orphan;Retain
+	// +kubebuilder:default=Delete
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// FinalState is the State of the Sample once all objects of the manifest are healthy.
+	// It overrides the --final-state flag of the operator, e.g. to mimic a module in Warning state.
+	// +kubebuilder:validation:Enum=Processing;Ready;Error;Warning
+	// +optional
+	FinalState State `json:"finalState,omitempty"`
+
+	// FinalDeletionState is the State of the Sample once it is marked for deletion. The objects of the manifest
+	// are only deleted in Deleting state, so any other State keeps the Sample from being removed.
+	// It overrides the --final-deletion-state flag of the operator.
+	// +kubebuilder:validation:Enum=Processing;Deleting;Ready;Error;Warning
+	// +optional
+	FinalDeletionState State `json:"finalDeletionState,omitempty"`
+
+	// Suspend stops applying, healing, pruning and deleting the objects of the manifest, e.g. to hot-fix them
+	// during an incident. The health of the applied objects is still reported. The annotation
+	// operator.kyma-project.io/paused: "true" suspends the reconciliation as well.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// DryRun plans the changes applying the manifest would cause and records them in the status,
+	// instead of applying the manifest. Nothing is created, changed, pruned or deleted in the cluster.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// ManifestParsing determines how documents of the manifest which are not valid objects are handled.
+	// Strict refuses to apply the manifest, Lenient skips them. Both report them in the ManifestInvalid condition.
+	// +kubebuilder:validation:Enum=Strict;Lenient
+	// +kubebuilder:default=Strict
+	// +optional
+	ManifestParsing ManifestParsing `json:"manifestParsing,omitempty"`
+
+	// MaxStatusResources caps the number of objects listed in the status. The objects which failed or are not
+	// healthy are kept. It overrides the --max-status-resources flag of the operator.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxStatusResources int32 `json:"maxStatusResources,omitempty"`
+
+	// Scenario scripts the States the Sample reports, e.g. to test how Lifecycle Manager handles them.
+	// The objects of the manifest are still applied, but the State is taken from the Scenario instead of
+	// their health. It does not apply once the Sample is deleted.
+	// +optional
+	Scenario *Scenario `json:"scenario,omitempty"`
+}
+
+// Scenario is a sequence of States reported by a Sample. Exactly one of Steps and ConfigMapName is set.
+type Scenario struct {
+	// Steps are run in order. Each step reports its State until its Duration elapsed or the Sample was
+	// reconciled the number of Reconciles, whichever comes first. The last step is kept once it is reached.
+	// +optional
+	Steps []ScenarioStep `json:"steps,omitempty"`
+
+	// ConfigMapName references a ConfigMap in the namespace of the Sample holding the steps as a YAML list
+	// in its "steps" key. It is read with every reconciliation, so the steps can be changed while they run.
+	// +optional
+	ConfigMapName string `json:"configMapName,omitempty"`
+}
+
+// ScenarioStep is a State reported by a Sample for a while.
+type ScenarioStep struct {
+	// +kubebuilder:validation:Enum=Processing;Ready;Error;Warning
+	State State `json:"state"`
+
+	// Duration for which the State is reported, e.g. 30s.
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// Reconciles is the number of reconciliations for which the State is reported.
+	// Reconciliations during a Scenario are at least 3 seconds apart.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Reconciles int32 `json:"reconciles,omitempty"`
+
+	// Message is reported in the Scenario condition during the step.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ManifestParsing determines how invalid documents of a manifest are handled.
+type ManifestParsing string
+
+const (
+	// ManifestParsingStrict refuses to apply a manifest with invalid documents.
+	ManifestParsingStrict ManifestParsing = "Strict"
+	// ManifestParsingLenient skips invalid documents and applies the valid objects of a manifest.
+	ManifestParsingLenient ManifestParsing = "Lenient"
+)
+
+// DriftPolicy determines how the drift of applied objects from the manifest is handled.
+type DriftPolicy string
+
+const (
+	// DriftPolicyRemediate reverts the drift by applying the manifest again.
+	DriftPolicyRemediate DriftPolicy = "Remediate"
+	// DriftPolicyReport only reports the drift, drifted objects are not applied.
+	DriftPolicyReport DriftPolicy = "Report"
+)
+
+// DeletionPolicy determines how the objects of the manifest are handled when the Sample is deleted.
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete deletes the objects.
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyOrphan leaves the objects in the cluster as they are, still labeled with the Sample,
+	// so that a Sample with the same name takes them over again.
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+	// DeletionPolicyRetain leaves the objects in the cluster and removes the operator labels from them.
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+)
+
+// CustomHealthCheck determines the health of all objects of a kind by one of their status conditions.
+type CustomHealthCheck struct {
+	// Group of the kind, empty for the core group.
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// +kubebuilder:validation:MinLength=1
+	Kind string `json:"kind"`
+
+	// ConditionType is the type of the status condition which is True once an object is healthy, e.g. Ready.
+	// +kubebuilder:validation:MinLength=1
+	ConditionType string `json:"conditionType"`
+
+	// DegradedConditionType is the type of an optional status condition which is True once an object
+	// failed and is not expected to recover, e.g. Stalled.
+	// +optional
+	DegradedConditionType string `json:"degradedConditionType,omitempty"`
+}
+
+// HelmSource describes a local Helm chart and the values used to render it.
+type HelmSource struct {
+	// ChartPath indicates the local path to a chart directory or to a packaged chart archive (.tgz).
+	// +kubebuilder:validation:MinLength=1
+	ChartPath string `json:"chartPath"`
+
+	// ValuesFiles indicates local paths to values files. They are merged in order on top of the
+	// chart's default values, so values of later files take precedence.
+	// +optional
+	ValuesFiles []string `json:"valuesFiles,omitempty"`
+
+	// Values are inline values merged on top of the ValuesFiles.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Values *runtime.RawExtension `json:"values,omitempty"`
+}
+
+// KustomizeSource describes a local kustomization and the overlay applied on top of it.
+type KustomizeSource struct {
+	// Path indicates the local dir path containing a kustomization.yaml.
+	// +kubebuilder:validation:MinLength=1
+	Path string `json:"path"`
+
+	// NamePrefix is prepended to the names of all built resources.
+	// +optional
+	NamePrefix string `json:"namePrefix,omitempty"`
+
+	// Namespace overrides the namespace of all namespaced built resources.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Patches are applied to the built resources before the NamePrefix and Namespace are set.
+	// +optional
+	Patches []KustomizePatch `json:"patches,omitempty"`
+}
+
+// KustomizePatch is an inline strategic merge or JSON 6902 patch.
+type KustomizePatch struct {
+	// Patch is the content of the patch.
+	// +kubebuilder:validation:MinLength=1
+	Patch string `json:"patch"`
+
+	// Target selects the resources the patch is applied to. It is required for JSON 6902 patches,
+	// strategic merge patches default to the resource identified by the patch itself.
+	// +optional
+	Target *KustomizePatchTarget `json:"target,omitempty"`
+}
+
+// KustomizePatchTarget selects resources by their group, version, kind, name, namespace, labels and annotations.
+// Group, version, kind, name and namespace are matched as regular expressions.
+type KustomizePatchTarget struct {
+	// +optional
+	Group string `json:"group,omitempty"`
+	// +optional
+	Version string `json:"version,omitempty"`
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// +optional
+	Name string `json:"name,omitempty"`
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// +optional
+	LabelSelector string `json:"labelSelector,omitempty"`
+	// +optional
+	AnnotationSelector string `json:"annotationSelector,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:storageversion
 //+kubebuilder:printcolumn:name="State",type=string,JSONPath=".status.state"
 
 // Sample is the Schema for the samples API.
@@ -1,5 +1,15 @@
 package v1alpha1
 
+import (
+	"unicode/utf8"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxConditionMessageLength is the maximum length of the message of a metav1.Condition.
+const maxConditionMessageLength = 32768
+
 type State string
 
 // Valid Module CR States.
@@ -35,3 +45,25 @@ type Status struct {
 	// +kubebuilder:validation:Enum=Processing;Deleting;Ready;Error;Warning;""
 	State State `json:"state"`
 }
+
+// setCondition sets the condition of the conditionType, truncating its message to the maximum length
+// at the start of a rune, so that it remains valid UTF-8.
+// Following the Kubernetes API conventions, its lastTransitionTime is only updated if its status changes.
+func setCondition(conditions *[]metav1.Condition, conditionType string, status metav1.ConditionStatus,
+	reason, message string, objGeneration int64,
+) {
+	if len(message) > maxConditionMessageLength {
+		cut := maxConditionMessageLength
+		for cut > 0 && !utf8.RuneStart(message[cut]) {
+			cut--
+		}
+		message = message[:cut]
+	}
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: objGeneration,
+	})
+}
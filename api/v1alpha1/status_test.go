@@ -0,0 +1,31 @@
+package v1alpha1_test
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+)
+
+func TestWithInstalledConditionTruncatesMessageAtRuneStart(t *testing.T) {
+	t.Parallel()
+	// the three byte rune is split by the maximum length of 32768 bytes
+	message := strings.Repeat("a", 32767) + strings.Repeat("€", 10)
+
+	status := (&v1alpha1.SampleStatus{}).WithInstalledCondition(metav1.ConditionTrue, "Installed", message, 1)
+
+	condition := meta.FindStatusCondition(status.Conditions, v1alpha1.ConditionTypeInstalled)
+	if condition == nil {
+		t.Fatal("expected the Installed condition to be set")
+	}
+	if !utf8.ValidString(condition.Message) {
+		t.Error("expected the truncated message to be valid UTF-8")
+	}
+	if len(condition.Message) != 32767 {
+		t.Errorf("expected the message to be truncated to 32767 bytes, got %d", len(condition.Message))
+	}
+}
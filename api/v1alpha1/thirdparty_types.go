@@ -20,11 +20,91 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// +kubebuilder:object:root=true
+const ThirdPartyKind Kind = "ThirdParty"
+
+// AdoptionMode determines how the objects selected by a ThirdParty are handled.
+type AdoptionMode string
+
+const (
+	// AdoptionModeObserve reports the health of the objects without managing them.
+	AdoptionModeObserve AdoptionMode = "Observe"
+	// AdoptionModeAdopt adds the objects to the adopted inventory of the Sample, so they are deleted with it.
+	AdoptionModeAdopt AdoptionMode = "Adopt"
+	// AdoptionModeTakeOwnership adopts the objects and takes over the ownership of all their fields,
+	// so that fields set by the previous tool are managed by the operator.
+	AdoptionModeTakeOwnership AdoptionMode = "TakeOwnership"
+)
+
+// ThirdPartySpec describes objects owned by another tool, selected by their kind and labels.
+// +kubebuilder:validation:XValidation:rule="self.mode == 'Observe' || has(self.sampleRef)",message="sampleRef is required to adopt objects"
+type ThirdPartySpec struct {
+	// APIVersion of the selected objects, e.g. apps/v1.
+	// +kubebuilder:validation:MinLength=1
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the selected objects, e.g. Deployment.
+	// +kubebuilder:validation:MinLength=1
+	Kind string `json:"kind"`
+
+	// Selector selects the objects by their labels in the namespace of the ThirdParty.
+	Selector metav1.LabelSelector `json:"selector"`
+
+	// Mode determines whether the selected objects are only observed, adopted by the Sample,
+	// or whether the ownership of their fields is taken over as well.
+	// +kubebuilder:validation:Enum=Observe;Adopt;TakeOwnership
+	// +kubebuilder:default=Observe
+	// +optional
+	Mode AdoptionMode `json:"mode,omitempty"`
+
+	// SampleRef references the Sample in the namespace of the ThirdParty adopting the selected objects.
+	// +optional
+	SampleRef *SampleReference `json:"sampleRef,omitempty"`
+}
+
+type ThirdPartyStatus struct {
+	Status `json:",inline"`
 
+	// Conditions contain a set of conditionals to determine the State of Status.
+	// If all Conditions are met, State is expected to be in StateReady.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Resources lists the selected objects with their health.
+	// +optional
+	Resources []ResourceStatus `json:"resources,omitempty"`
+}
+
+func (s *ThirdPartyStatus) WithState(state State) *ThirdPartyStatus {
+	s.State = state
+	return s
+}
+
+func (s *ThirdPartyStatus) WithResources(resources []ResourceStatus) *ThirdPartyStatus {
+	s.Resources = resources
+	return s
+}
+
+// WithCondition sets the condition of the conditionType.
+func (s *ThirdPartyStatus) WithCondition(conditionType string, status metav1.ConditionStatus, reason,
+	message string, objGeneration int64,
+) *ThirdPartyStatus {
+	setCondition(&s.Conditions, conditionType, status, reason, message, objGeneration)
+	return s
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Kind",type=string,JSONPath=".spec.kind"
+//+kubebuilder:printcolumn:name="Mode",type=string,JSONPath=".spec.mode"
+//+kubebuilder:printcolumn:name="State",type=string,JSONPath=".status.state"
+
+// ThirdParty is the Schema for the thirdparties API. It describes objects owned by another tool,
+// which are observed or adopted by a Sample, e.g. when migrating a legacy component into a module.
 type ThirdParty struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ThirdPartySpec   `json:"spec,omitempty"`
+	Status ThirdPartyStatus `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
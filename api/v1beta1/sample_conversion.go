@@ -0,0 +1,75 @@
+package v1beta1
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+)
+
+// ConvertTo converts this Sample to the Hub version (v1alpha1).
+func (src *Sample) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1alpha1.Sample)
+	if !ok {
+		return fmt.Errorf("%w: %T", errUnexpectedHub, dstRaw)
+	}
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Status = src.Status
+	dst.Spec = v1alpha1.SampleSpec{
+		Helm:               src.Spec.Source.Helm,
+		Kustomize:          src.Spec.Source.Kustomize,
+		HealthChecks:       src.Spec.HealthChecks,
+		DriftPolicy:        src.Spec.DriftPolicy,
+		DeletionPolicy:     src.Spec.DeletionPolicy,
+		FinalState:         src.Spec.FinalState,
+		FinalDeletionState: src.Spec.FinalDeletionState,
+		Suspend:            src.Spec.Suspend,
+		DryRun:             src.Spec.DryRun,
+		ManifestParsing:    src.Spec.ManifestParsing,
+		MaxStatusResources: src.Spec.MaxStatusResources,
+		Scenario:           src.Spec.Scenario,
+	}
+	if path := src.Spec.Source.Path; path != nil {
+		dst.Spec.ResourceFilePath = path.Path
+		dst.Spec.IncludePatterns = path.IncludePatterns
+		dst.Spec.ExcludePatterns = path.ExcludePatterns
+	}
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1alpha1) to this version.
+// The members of the source union are set as in the hub, so that a hub setting a ResourceFilePath
+// next to a Helm chart or kustomization still round-trips.
+func (dst *Sample) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1alpha1.Sample)
+	if !ok {
+		return fmt.Errorf("%w: %T", errUnexpectedHub, srcRaw)
+	}
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Status = src.Status
+	dst.Spec = SampleSpec{
+		Source: ManifestSource{
+			Helm:      src.Spec.Helm,
+			Kustomize: src.Spec.Kustomize,
+		},
+		HealthChecks:       src.Spec.HealthChecks,
+		DriftPolicy:        src.Spec.DriftPolicy,
+		DeletionPolicy:     src.Spec.DeletionPolicy,
+		FinalState:         src.Spec.FinalState,
+		FinalDeletionState: src.Spec.FinalDeletionState,
+		Suspend:            src.Spec.Suspend,
+		DryRun:             src.Spec.DryRun,
+		ManifestParsing:    src.Spec.ManifestParsing,
+		MaxStatusResources: src.Spec.MaxStatusResources,
+		Scenario:           src.Spec.Scenario,
+	}
+	if src.Spec.ResourceFilePath != "" || len(src.Spec.IncludePatterns) > 0 || len(src.Spec.ExcludePatterns) > 0 {
+		dst.Spec.Source.Path = &PathSource{
+			Path:            src.Spec.ResourceFilePath,
+			IncludePatterns: src.Spec.IncludePatterns,
+			ExcludePatterns: src.Spec.ExcludePatterns,
+		}
+	}
+	return nil
+}
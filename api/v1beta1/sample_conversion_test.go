@@ -0,0 +1,101 @@
+package v1beta1_test
+
+import (
+	"testing"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/diff"
+	"sigs.k8s.io/randfill"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+	"github.com/kyma-project/template-operator/api/v1beta1"
+)
+
+const roundTrips = 1000
+
+// fillerFuncs keep the fuzzed objects within what the API server accepts.
+func fillerFuncs() []any {
+	return []any{
+		// the TypeMeta is set by the conversion webhook and not part of the conversion
+		func(typeMeta *metav1.TypeMeta, _ randfill.Continue) {
+			*typeMeta = metav1.TypeMeta{}
+		},
+		func(ext *runtime.RawExtension, c randfill.Continue) {
+			*ext = runtime.RawExtension{}
+			if c.Bool() {
+				ext.Raw = []byte(`{"key":"` + c.String(0) + `"}`)
+			}
+		},
+		// a path source requires a path
+		func(path *v1beta1.PathSource, c randfill.Continue) {
+			c.FillNoCustom(path)
+			if path.Path == "" {
+				path.Path = "./module-data/yaml"
+			}
+		},
+	}
+}
+
+func TestSampleConversionRoundTrip(t *testing.T) {
+	t.Parallel()
+	filler := randfill.NewWithSeed(1).NilChance(0.3).NumElements(0, 2).Funcs(fillerFuncs()...)
+	for range roundTrips {
+		hub := &v1alpha1.Sample{}
+		filler.Fill(hub)
+		checkHubSpokeHub(t, hub)
+
+		spoke := &v1beta1.Sample{}
+		filler.Fill(spoke)
+		checkSpokeHubSpoke(t, spoke)
+	}
+}
+
+func FuzzSampleHubSpokeHub(f *testing.F) {
+	f.Add([]byte("sample"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		hub := &v1alpha1.Sample{}
+		randfill.NewFromGoFuzz(data).Funcs(fillerFuncs()...).Fill(hub)
+		checkHubSpokeHub(t, hub)
+	})
+}
+
+func FuzzSampleSpokeHubSpoke(f *testing.F) {
+	f.Add([]byte("sample"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		spoke := &v1beta1.Sample{}
+		randfill.NewFromGoFuzz(data).Funcs(fillerFuncs()...).Fill(spoke)
+		checkSpokeHubSpoke(t, spoke)
+	})
+}
+
+func checkHubSpokeHub(t *testing.T, hub *v1alpha1.Sample) {
+	t.Helper()
+	spoke := &v1beta1.Sample{}
+	if err := spoke.ConvertFrom(hub.DeepCopy()); err != nil {
+		t.Fatalf("error converting from hub: %v", err)
+	}
+	converted := &v1alpha1.Sample{}
+	if err := spoke.ConvertTo(converted); err != nil {
+		t.Fatalf("error converting to hub: %v", err)
+	}
+	if !apiequality.Semantic.DeepEqual(hub, converted) {
+		t.Errorf("hub changed after round-trip through v1beta1:\n%s", diff.Diff(hub, converted))
+	}
+}
+
+func checkSpokeHubSpoke(t *testing.T, spoke *v1beta1.Sample) {
+	t.Helper()
+	hub := &v1alpha1.Sample{}
+	if err := spoke.DeepCopy().ConvertTo(hub); err != nil {
+		t.Fatalf("error converting to hub: %v", err)
+	}
+	converted := &v1beta1.Sample{}
+	if err := converted.ConvertFrom(hub); err != nil {
+		t.Fatalf("error converting from hub: %v", err)
+	}
+	if !apiequality.Semantic.DeepEqual(spoke, converted) {
+		t.Errorf("v1beta1 changed after round-trip through hub:\n%s", diff.Diff(spoke, converted))
+	}
+}
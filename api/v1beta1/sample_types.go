@@ -0,0 +1,168 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1beta1 contains API Schema definitions for the component v1beta1 API group.
+// Its types are converted from and to the v1alpha1 hub, which is the storage version.
+// +kubebuilder:object:generate=true
+// +groupName=operator.kyma-project.io
+//
+//nolint:gochecknoglobals // required for utilizing the API
+package v1beta1
+
+import (
+	"errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+)
+
+var (
+	// GroupVersion is group version used to register these objects.
+	GroupVersion = schema.GroupVersion{Group: "operator.kyma-project.io", Version: "v1beta1"}
+
+	errUnexpectedHub = errors.New("unexpected hub type")
+)
+
+type SampleSpec struct {
+	// Source of the manifest of resources processed for the Sample.
+	Source ManifestSource `json:"source"`
+
+	// HealthChecks define the status conditions indicating the health of kinds
+	// without a built-in health evaluation.
+	// +optional
+	HealthChecks []v1alpha1.CustomHealthCheck `json:"healthChecks,omitempty"`
+
+	// DriftPolicy determines how changes of the applied objects made outside of the operator are handled.
+	// Remediate reverts them, Report only reports them in the Drifted condition and leaves the objects untouched.
+	// +kubebuilder:validation:Enum=Remediate;Report
+	// +kubebuilder:default=Remediate
+	// +optional
+	DriftPolicy v1alpha1.DriftPolicy `json:"driftPolicy,omitempty"`
+
+	// DeletionPolicy determines what happens to the objects of the manifest when the Sample is deleted.
+	// Delete removes them, Orphan leaves them untouched, and Retain leaves them stripped of the operator labels.
+	// The annotation operator.kyma-project.io/deletion-policy overrides it for single objects.
+	// +kubebuilder:validation:Enum=Delete;Orphan;Retain
+	// +kubebuilder:default=Delete
+	// +optional
+	DeletionPolicy v1alpha1.DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// FinalState is the State of the Sample once all objects of the manifest are healthy.
+	// It overrides the --final-state flag of the operator, e.g. to mimic a module in Warning state.
+	// +kubebuilder:validation:Enum=Processing;Ready;Error;Warning
+	// +optional
+	FinalState v1alpha1.State `json:"finalState,omitempty"`
+
+	// FinalDeletionState is the State of the Sample once it is marked for deletion. The objects of the manifest
+	// are only deleted in Deleting state, so any other State keeps the Sample from being removed.
+	// It overrides the --final-deletion-state flag of the operator.
+	// +kubebuilder:validation:Enum=Processing;Deleting;Ready;Error;Warning
+	// +optional
+	FinalDeletionState v1alpha1.State `json:"finalDeletionState,omitempty"`
+
+	// Suspend stops applying, healing, pruning and deleting the objects of the manifest, e.g. to hot-fix them
+	// during an incident. The health of the applied objects is still reported. The annotation
+	// operator.kyma-project.io/paused: "true" suspends the reconciliation as well.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// DryRun plans the changes applying the manifest would cause and records them in the status,
+	// instead of applying the manifest. Nothing is created, changed, pruned or deleted in the cluster.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// ManifestParsing determines how documents of the manifest which are not valid objects are handled.
+	// Strict refuses to apply the manifest, Lenient skips them. Both report them in the ManifestInvalid condition.
+	// +kubebuilder:validation:Enum=Strict;Lenient
+	// +kubebuilder:default=Strict
+	// +optional
+	ManifestParsing v1alpha1.ManifestParsing `json:"manifestParsing,omitempty"`
+
+	// MaxStatusResources caps the number of objects listed in the status. The objects which failed or are not
+	// healthy are kept. It overrides the --max-status-resources flag of the operator.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxStatusResources int32 `json:"maxStatusResources,omitempty"`
+
+	// Scenario scripts the States the Sample reports, e.g. to test how Lifecycle Manager handles them.
+	// The objects of the manifest are still applied, but the State is taken from the Scenario instead of
+	// their health. It does not apply once the Sample is deleted.
+	// +optional
+	Scenario *v1alpha1.Scenario `json:"scenario,omitempty"`
+}
+
+// ManifestSource is a union of the sources a manifest is loaded from. Exactly one of its members is set.
+// +kubebuilder:validation:XValidation:rule="[has(self.path), has(self.helm), has(self.kustomize)].filter(x, x).size() == 1",message="exactly one of path, helm and kustomize must be set"
+type ManifestSource struct {
+	// Path loads pre-rendered manifests from .yaml and .yml files on the local file system.
+	// +optional
+	Path *PathSource `json:"path,omitempty"`
+
+	// Helm references a Helm chart that is rendered in-process.
+	// The release name and namespace are taken from the Sample.
+	// +optional
+	Helm *v1alpha1.HelmSource `json:"helm,omitempty"`
+
+	// Kustomize references a kustomization directory that is built in-process.
+	// +optional
+	Kustomize *v1alpha1.KustomizeSource `json:"kustomize,omitempty"`
+}
+
+// PathSource describes the local files containing pre-rendered manifests.
+type PathSource struct {
+	// Path indicates the local dir path containing .yaml or .yml files,
+	// with all required resources to be processed.
+	// Nested directories are walked recursively and the files are loaded in lexical order
+	// of their path relative to Path. A path to a single file is also accepted.
+	// +kubebuilder:validation:MinLength=1
+	Path string `json:"path"`
+
+	// IncludePatterns restricts the loaded .yaml and .yml files to those matching at least one of the
+	// glob patterns. Patterns containing a "/" are matched against the path relative to Path,
+	// all other patterns are matched against the file name. If empty, all files are included.
+	// +optional
+	IncludePatterns []string `json:"includePatterns,omitempty"`
+
+	// ExcludePatterns skips files matching any of the glob patterns, following the same matching
+	// rules as IncludePatterns. Exclusion takes precedence over inclusion.
+	// +optional
+	ExcludePatterns []string `json:"excludePatterns,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="State",type=string,JSONPath=".status.state"
+
+// Sample is the Schema for the samples API.
+type Sample struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SampleSpec            `json:"spec,omitempty"`
+	Status v1alpha1.SampleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SampleList contains a list of Sample.
+type SampleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []Sample `json:"items"`
+}
@@ -0,0 +1,158 @@
+package controllers
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+)
+
+const (
+	// applyWaveAnnotation assigns an object of the manifest to an apply wave. Objects without it belong to wave 0.
+	applyWaveAnnotation = "operator.kyma-project.io/apply-wave"
+)
+
+var (
+	errInvalidApplyWave = errors.New("invalid apply wave")
+	// errCRDNotEstablished defers applying custom resources to a later reconciliation, until their CRD is established
+	errCRDNotEstablished = errors.New("waiting for CRD to be established")
+)
+
+// applyWave is a set of objects which is applied together,
+// once all objects of the preceding waves are healthy.
+type applyWave struct {
+	wave      int32
+	resources []*unstructured.Unstructured
+}
+
+// applyOrder lists the kinds in the order they are applied, following the install order of Helm.
+// Kinds which are not listed, such as custom resources, are applied after all listed kinds.
+//
+//nolint:gochecknoglobals // immutable list of kinds
+var applyOrder = []string{
+	"Namespace",
+	"NetworkPolicy",
+	"ResourceQuota",
+	"LimitRange",
+	"PodSecurityPolicy",
+	"PodDisruptionBudget",
+	"ServiceAccount",
+	"Secret",
+	"SecretList",
+	"ConfigMap",
+	"StorageClass",
+	"PersistentVolume",
+	"PersistentVolumeClaim",
+	"CustomResourceDefinition",
+	"ClusterRole",
+	"ClusterRoleList",
+	"ClusterRoleBinding",
+	"ClusterRoleBindingList",
+	"Role",
+	"RoleList",
+	"RoleBinding",
+	"RoleBindingList",
+	"Service",
+	"DaemonSet",
+	"Pod",
+	"ReplicationController",
+	"ReplicaSet",
+	"Deployment",
+	"HorizontalPodAutoscaler",
+	"StatefulSet",
+	"Job",
+	"CronJob",
+	"IngressClass",
+	"Ingress",
+	"APIService",
+	"MutatingWebhookConfiguration",
+	"ValidatingWebhookConfiguration",
+}
+
+// getApplyPriority returns the position of the kind in the applyOrder, or its length for kinds not listed.
+func getApplyPriority(kind string) int {
+	if priority := slices.Index(applyOrder, kind); priority >= 0 {
+		return priority
+	}
+	return len(applyOrder)
+}
+
+// getApplyWave returns the apply wave of the object, which defaults to 0 if it is not annotated.
+func getApplyWave(obj *unstructured.Unstructured) (int32, error) {
+	value, found := obj.GetAnnotations()[applyWaveAnnotation]
+	if !found {
+		return 0, nil
+	}
+	wave, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%w %q of %s: %w", errInvalidApplyWave, value, describeObject(obj), err)
+	}
+	return int32(wave), nil
+}
+
+// sortResourcesForApply sorts the objects by their apply wave and the apply priority of their kind.
+// Objects of the same wave and priority keep their manifest order.
+// Objects with an invalid apply wave are sorted into wave 0, splitApplyWaves reports them.
+func sortResourcesForApply(resources []*unstructured.Unstructured) {
+	slices.SortStableFunc(resources, func(a, b *unstructured.Unstructured) int {
+		waveA, _ := getApplyWave(a)
+		waveB, _ := getApplyWave(b)
+		return cmp.Or(cmp.Compare(waveA, waveB),
+			cmp.Compare(getApplyPriority(a.GetKind()), getApplyPriority(b.GetKind())))
+	})
+}
+
+// splitApplyWaves splits the objects, sorted by sortResourcesForApply, into their apply waves.
+func splitApplyWaves(resources []*unstructured.Unstructured) ([]applyWave, error) {
+	waves := make([]applyWave, 0, 1)
+	for _, obj := range resources {
+		wave, err := getApplyWave(obj)
+		if err != nil {
+			return nil, err
+		}
+		if len(waves) == 0 || waves[len(waves)-1].wave != wave {
+			waves = append(waves, applyWave{wave: wave})
+		}
+		waves[len(waves)-1].resources = append(waves[len(waves)-1].resources, obj)
+	}
+	return waves, nil
+}
+
+// getManifestCRDs returns the CRDs of the manifest, indexed by the kind they define.
+func getManifestCRDs(resources []*unstructured.Unstructured) map[schema.GroupKind]*unstructured.Unstructured {
+	crds := map[schema.GroupKind]*unstructured.Unstructured{}
+	for _, obj := range resources {
+		if obj.GroupVersionKind().GroupKind() != crdGroupKind {
+			continue
+		}
+		group, _, _ := unstructured.NestedString(obj.Object, "spec", "group")
+		kind, _, _ := unstructured.NestedString(obj.Object, "spec", "names", "kind")
+		crds[schema.GroupKind{Group: group, Kind: kind}] = obj
+	}
+	return crds
+}
+
+// checkCRDEstablished returns errCRDNotEstablished unless the CRD is established,
+// so that its custom resources can be applied.
+func (r *SampleReconciler) checkCRDEstablished(ctx context.Context, crd *unstructured.Unstructured) error {
+	live := &unstructured.Unstructured{}
+	live.SetGroupVersionKind(crd.GroupVersionKind())
+	if err := r.Get(ctx, client.ObjectKeyFromObject(crd), live); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("error while getting CRD %s: %w", crd.GetName(), err)
+		}
+		return fmt.Errorf("%w: %s", errCRDNotEstablished, crd.GetName())
+	}
+	if health, _ := evaluateCRDHealth(live); health != v1alpha1.HealthHealthy {
+		return fmt.Errorf("%w: %s", errCRDNotEstablished, crd.GetName())
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package controllers_test
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const orderingNamespace = "ordering"
+
+var _ = Describe("Sample CR is created with resources depending on each other", Ordered, func() {
+	sampleCR := createSampleCR("ordering-sample", "./test/ordering")
+	sampleCRKey := client.ObjectKeyFromObject(sampleCR)
+	crdKey := client.ObjectKey{Name: "widgets.ordering.kyma-project.io"}
+	widgetKey := client.ObjectKey{Namespace: orderingNamespace, Name: "ordering-widget"}
+
+	It("should apply Namespaces and CRDs before the objects depending on them", func() {
+		Expect(k8sClient.Create(ctx, sampleCR)).To(Succeed())
+
+		Eventually(getCRStatus(sampleCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Equal(CRStatus{State: v1alpha1.StateReady, InstallConditionStatus: metav1.ConditionTrue, Err: nil}))
+
+		Expect(k8sClient.Get(ctx, widgetKey, getWidget())).To(Succeed())
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: orderingNamespace, Name: "ordering-config"},
+			&v1.ConfigMap{})).To(Succeed())
+		Expect(getInventoryNames(sampleCRKey)(Default)).To(Equal([]string{
+			orderingNamespace, "ordering-config", crdKey.Name, widgetKey.Name,
+		}))
+	})
+
+	It("should delete the resources in reverse order with the SampleCR", func() {
+		Expect(k8sClient.Delete(ctx, sampleCR)).To(Succeed())
+
+		Eventually(func() bool {
+			return errors.IsNotFound(k8sClient.Get(ctx, crdKey, getCRD())) &&
+				errors.IsNotFound(k8sClient.Get(ctx, sampleCRKey, &v1alpha1.Sample{}))
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeTrue())
+	})
+})
+
+func getWidget() *unstructured.Unstructured {
+	widget := &unstructured.Unstructured{}
+	widget.SetGroupVersionKind(schema.GroupVersionKind{Group: "ordering.kyma-project.io", Version: "v1", Kind: "Widget"})
+	return widget
+}
+
+func getCRD() *unstructured.Unstructured {
+	crd := &unstructured.Unstructured{}
+	crd.SetGroupVersionKind(schema.GroupVersionKind{
+		Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition",
+	})
+	return crd
+}
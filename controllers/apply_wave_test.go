@@ -0,0 +1,73 @@
+package controllers_test
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sample CR is created with resources in multiple apply waves", Ordered, func() {
+	sampleCR := createSampleCR("wave-sample", "./test/waves")
+	sampleCRKey := client.ObjectKeyFromObject(sampleCR)
+	migrationPodName := "wave-migration"
+	configKey := client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "wave-config"}
+
+	It("should not apply the next wave before the current wave is healthy", func() {
+		Expect(k8sClient.Create(ctx, sampleCR)).To(Succeed())
+
+		Eventually(getCurrentWave(sampleCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Equal(ptr.To[int32](0)))
+		Consistently(func() bool {
+			return errors.IsNotFound(k8sClient.Get(ctx, configKey, &v1.ConfigMap{}))
+		}).
+			WithTimeout(5 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeTrue())
+		Expect(getCRStatus(sampleCRKey)(Default).State).To(Equal(v1alpha1.StateProcessing))
+	})
+
+	It("should apply the next wave once the current wave is healthy", func() {
+		Eventually(getPod(metav1.NamespaceDefault, migrationPodName)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeTrue())
+
+		Eventually(getCRStatus(sampleCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Equal(CRStatus{State: v1alpha1.StateReady, InstallConditionStatus: metav1.ConditionTrue, Err: nil}))
+		Expect(getCurrentWave(sampleCRKey)(Default)).To(Equal(ptr.To[int32](1)))
+		Expect(k8sClient.Get(ctx, configKey, &v1.ConfigMap{})).To(Succeed())
+		Expect(getInventoryNames(sampleCRKey)(Default)).To(Equal([]string{migrationPodName, configKey.Name}))
+	})
+
+	It("should delete the resources with the SampleCR", func() {
+		Expect(k8sClient.Delete(ctx, sampleCR)).To(Succeed())
+		Eventually(func() bool {
+			return errors.IsNotFound(k8sClient.Get(ctx, configKey, &v1.ConfigMap{})) &&
+				errors.IsNotFound(k8sClient.Get(ctx, sampleCRKey, &v1alpha1.Sample{}))
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeTrue())
+	})
+})
+
+func getCurrentWave(sampleObjKey client.ObjectKey) func(g Gomega) *int32 {
+	return func(gomega Gomega) *int32 {
+		sampleCR := &v1alpha1.Sample{}
+		gomega.Expect(k8sClient.Get(ctx, sampleObjKey, sampleCR)).To(Succeed())
+		return sampleCR.Status.CurrentWave
+	}
+}
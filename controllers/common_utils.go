@@ -1,20 +1,20 @@
 package controllers
 
 import (
-	"bufio"
 	"bytes"
 	"errors"
 	"fmt"
-	"io"
 	"strings"
 	"time"
 
 	"golang.org/x/time/rate"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	yamlUtil "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/apimachinery/pkg/util/json"
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/yaml"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
 )
 
 type RateLimiter struct {
@@ -26,37 +26,111 @@ type RateLimiter struct {
 
 const (
 	requeueInterval = time.Second * 3
-	finalizer       = "sample.kyma-project.io/finalizer"
-	debugLogLevel   = 2
-	fieldOwner      = "sample.kyma-project.io/owner"
+	// defaultHealthCheckTimeout is used if no HealthCheckTimeout is configured on the SampleReconciler
+	defaultHealthCheckTimeout = time.Minute * 5
+	// defaultResyncPeriod is used if no ResyncPeriod is configured on the SampleReconciler
+	defaultResyncPeriod = time.Minute * 10
+	// defaultMaxStatusResources is used if no MaxStatusResources is configured on the SampleReconciler
+	defaultMaxStatusResources = 100
+	// defaultDeletionBlockedState is used if no DeletionBlockedState is configured on the SampleReconciler
+	defaultDeletionBlockedState = v1alpha1.StateWarning
+	// deletionBlockedRequeueInterval after which a Sample whose deletion is blocked checks the blocking objects again,
+	// as they are not watched
+	deletionBlockedRequeueInterval = time.Second * 30
+	finalizer                      = "sample.kyma-project.io/finalizer"
+	debugLogLevel                  = 2
+	fieldOwner                     = "sample.kyma-project.io/owner"
+	// yamlDocumentSeparator separates the documents of a multi-document YAML manifest
+	yamlDocumentSeparator = "---"
+)
+
+var (
+	errDocumentNotAnObject = errors.New("document is not an object")
+	errMissingAPIVersion   = errors.New("object has no apiVersion")
+	errMissingKind         = errors.New("object has no kind")
+	errMissingName         = errors.New("object has no metadata.name")
 )
 
+// invalidDocumentError describes a document of a manifest which is not a valid object.
+type invalidDocumentError struct {
+	source string
+	// index is the 1-based position of the document among the non-empty documents of the source
+	index int
+	// line is the line the document starts at in the source
+	line int
+	err  error
+}
+
+func (e *invalidDocumentError) Error() string {
+	return fmt.Sprintf("document %d at line %d of %s: %v", e.index, e.line, e.source, e.err)
+}
+
+func (e *invalidDocumentError) Unwrap() error {
+	return e.err
+}
+
 // parseManifestStringToObjects parses the string of resources into a list of unstructured resources.
-func parseManifestStringToObjects(manifest string) (*ManifestResources, error) {
+// Documents which are not valid objects are collected as invalid documents of the source.
+func parseManifestStringToObjects(source, manifest string) *ManifestResources {
 	objects := &ManifestResources{}
-	reader := yamlUtil.NewYAMLReader(bufio.NewReader(strings.NewReader(manifest)))
-	for {
-		rawBytes, err := reader.Read()
-		if err != nil {
-			if errors.Is(err, io.EOF) {
-				return objects, nil
+	index := 0
+	for _, document := range splitManifestDocuments(manifest) {
+		jsonBytes, err := yaml.YAMLToJSON(document.content)
+		if err == nil && (len(bytes.TrimSpace(jsonBytes)) == 0 || bytes.Equal(jsonBytes, []byte("null"))) {
+			continue
+		}
+		index++
+		if err == nil {
+			var obj *unstructured.Unstructured
+			if obj, err = parseManifestObject(jsonBytes); err == nil {
+				objects.Items = append(objects.Items, obj)
+				continue
 			}
-
-			return nil, fmt.Errorf("invalid YAML doc: %w", err)
 		}
+		objects.Invalid = append(objects.Invalid, &invalidDocumentError{
+			source: source, index: index, line: document.line, err: err,
+		})
+	}
+	return objects
+}
 
-		rawBytes = bytes.TrimSpace(rawBytes)
-		unstructuredObj := unstructured.Unstructured{}
-		if err := yaml.Unmarshal(rawBytes, &unstructuredObj); err != nil {
-			objects.Blobs = append(objects.Blobs, append(bytes.TrimPrefix(rawBytes, []byte("---\n")), '\n'))
-		}
+func parseManifestObject(jsonBytes []byte) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	if err := json.Unmarshal(jsonBytes, &obj.Object); err != nil || obj.Object == nil {
+		return nil, errDocumentNotAnObject
+	}
+	switch {
+	case obj.GetAPIVersion() == "":
+		return nil, errMissingAPIVersion
+	case obj.GetKind() == "":
+		return nil, errMissingKind
+	case obj.GetName() == "":
+		return nil, errMissingName
+	}
+	return obj, nil
+}
 
-		if len(rawBytes) == 0 || bytes.Equal(rawBytes, []byte("null")) || len(unstructuredObj.Object) == 0 {
+// manifestDocument is a single YAML document of a manifest.
+type manifestDocument struct {
+	// line is the line the document starts at in the manifest
+	line    int
+	content []byte
+}
+
+// splitManifestDocuments splits the manifest at its document separators.
+func splitManifestDocuments(manifest string) []manifestDocument {
+	documents := make([]manifestDocument, 0)
+	current := manifestDocument{line: 1}
+	for i, line := range strings.Split(manifest, "\n") {
+		trimmed := strings.TrimRight(line, " \t\r")
+		if trimmed == yamlDocumentSeparator || strings.HasPrefix(trimmed, yamlDocumentSeparator+" ") {
+			documents = append(documents, current)
+			current = manifestDocument{line: i + 2}
 			continue
 		}
-
-		objects.Items = append(objects.Items, &unstructuredObj)
+		current.content = append(append(current.content, line...), '\n')
 	}
+	return append(documents, current)
 }
 
 // TemplateRateLimiter implements a rate limiter for a client-go.workqueue.  It has
@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+)
+
+// conditionError is an error of processing the resources, with the reason reported in the conditions.
+type conditionError struct {
+	reason string
+	err    error
+}
+
+func (e *conditionError) Error() string {
+	return e.err.Error()
+}
+
+func (e *conditionError) Unwrap() error {
+	return e.err
+}
+
+// withConditionReason attaches the reason reported in the conditions to the error.
+func withConditionReason(reason string, err error) error {
+	return &conditionError{reason: reason, err: err}
+}
+
+// getConditionReason returns the reason attached to the error, defaulting to ApplyFailed.
+func getConditionReason(err error) string {
+	var condErr *conditionError
+	if errors.As(err, &condErr) {
+		return condErr.reason
+	}
+	return v1alpha1.ConditionReasonApplyFailed
+}
+
+// setInstallConditions sets the Installed, Progressing and Degraded conditions, all explained by reason and message.
+func setInstallConditions(status *v1alpha1.SampleStatus, installed, progressing, degraded metav1.ConditionStatus,
+	reason, message string, objGeneration int64,
+) {
+	status.WithInstalledCondition(installed, reason, message, objGeneration).
+		WithCondition(v1alpha1.ConditionTypeProgressing, progressing, reason, message, objGeneration).
+		WithCondition(v1alpha1.ConditionTypeDegraded, degraded, reason, message, objGeneration)
+}
+
+// setProgressingConditions marks the installation as in progress.
+func setProgressingConditions(status *v1alpha1.SampleStatus, reason, message string, objGeneration int64) {
+	setInstallConditions(status, metav1.ConditionUnknown, metav1.ConditionTrue, metav1.ConditionFalse,
+		reason, message, objGeneration)
+}
+
+// setReadyConditions marks the installation as completed with all resources healthy.
+func setReadyConditions(status *v1alpha1.SampleStatus, objGeneration int64) {
+	setInstallConditions(status, metav1.ConditionTrue, metav1.ConditionFalse, metav1.ConditionFalse,
+		v1alpha1.ConditionReasonReady, "installation is ready and resources can be used", objGeneration)
+}
+
+// setFailedConditions marks the installation as failed, with the error or degradation in the message.
+func setFailedConditions(status *v1alpha1.SampleStatus, reason, message string, objGeneration int64) {
+	setInstallConditions(status, metav1.ConditionFalse, metav1.ConditionFalse, metav1.ConditionTrue,
+		reason, message, objGeneration)
+}
+
+// setDryRunConditions marks the installation as planned only, as nothing is applied in dry-run mode.
+func setDryRunConditions(status *v1alpha1.SampleStatus, objGeneration int64) {
+	setInstallConditions(status, metav1.ConditionUnknown, metav1.ConditionFalse, metav1.ConditionFalse,
+		v1alpha1.ConditionReasonDryRun, "dry-run planned the changes without applying them", objGeneration)
+}
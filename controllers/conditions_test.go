@@ -0,0 +1,89 @@
+package controllers_test
+
+import (
+	"os"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sample CR is created with a manifest path which does not exist", Ordered, func() {
+	sampleCR := createSampleCR("conditions-sample", "")
+	sampleCRKey := client.ObjectKeyFromObject(sampleCR)
+	configKey := client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "conditions-config"}
+
+	It("should report the missing manifest in the conditions", func() {
+		// the admission webhook only admits existing paths, so the path is removed after the Sample is created
+		sampleCR.Spec.ResourceFilePath = createTempManifestPath("does-not-exist")
+		Expect(k8sClient.Create(ctx, sampleCR)).To(Succeed())
+		Expect(os.RemoveAll(sampleCR.Spec.ResourceFilePath)).To(Succeed())
+
+		Eventually(getCRStatus(sampleCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Equal(CRStatus{State: v1alpha1.StateError, InstallConditionStatus: metav1.ConditionFalse, Err: nil}))
+		Expect(getCondition(sampleCRKey, v1alpha1.ConditionTypeInstalled)(Default)).To(And(
+			HaveField("Status", metav1.ConditionFalse),
+			HaveField("Reason", v1alpha1.ConditionReasonManifestNotFound),
+			HaveField("Message", ContainSubstring("does-not-exist")),
+		))
+		Expect(getCondition(sampleCRKey, v1alpha1.ConditionTypeInstallation)(Default)).
+			To(HaveField("Reason", v1alpha1.ConditionReasonManifestNotFound))
+		Expect(getCondition(sampleCRKey, v1alpha1.ConditionTypeDegraded)(Default)).To(And(
+			HaveField("Status", metav1.ConditionTrue),
+			HaveField("Reason", v1alpha1.ConditionReasonManifestNotFound),
+		))
+		Expect(getCondition(sampleCRKey, v1alpha1.ConditionTypeProgressing)(Default)).
+			To(HaveField("Status", metav1.ConditionFalse))
+	})
+
+	It("should report the installation as ready once the manifest is found", func() {
+		Expect(k8sClient.Get(ctx, sampleCRKey, sampleCR)).To(Succeed())
+		sampleCR.Spec.ResourceFilePath = createConfigMapManifest(configKey.Name)
+		Expect(k8sClient.Update(ctx, sampleCR)).To(Succeed())
+
+		Eventually(getCRStatus(sampleCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Equal(CRStatus{State: v1alpha1.StateReady, InstallConditionStatus: metav1.ConditionTrue, Err: nil}))
+		Expect(getCondition(sampleCRKey, v1alpha1.ConditionTypeInstalled)(Default)).To(And(
+			HaveField("Status", metav1.ConditionTrue),
+			HaveField("Reason", v1alpha1.ConditionReasonReady),
+		))
+		Expect(getCondition(sampleCRKey, v1alpha1.ConditionTypeDegraded)(Default)).
+			To(HaveField("Status", metav1.ConditionFalse))
+		Expect(getCondition(sampleCRKey, v1alpha1.ConditionTypeProgressing)(Default)).
+			To(HaveField("Status", metav1.ConditionFalse))
+		Expect(k8sClient.Get(ctx, configKey, &v1.ConfigMap{})).To(Succeed())
+	})
+
+	It("should delete the resources with the SampleCR", func() {
+		Expect(k8sClient.Delete(ctx, sampleCR)).To(Succeed())
+		Eventually(func() bool {
+			return errors.IsNotFound(k8sClient.Get(ctx, configKey, &v1.ConfigMap{})) &&
+				errors.IsNotFound(k8sClient.Get(ctx, sampleCRKey, &v1alpha1.Sample{}))
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeTrue())
+	})
+})
+
+func getCondition(sampleObjKey client.ObjectKey, conditionType string) func(g Gomega) metav1.Condition {
+	return func(gomega Gomega) metav1.Condition {
+		sampleCR := &v1alpha1.Sample{}
+		gomega.Expect(k8sClient.Get(ctx, sampleObjKey, sampleCR)).To(Succeed())
+		condition := meta.FindStatusCondition(sampleCR.Status.Conditions, conditionType)
+		gomega.Expect(condition).NotTo(BeNil())
+		return *condition
+	}
+}
@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+)
+
+const (
+	// forceDeleteAnnotation deletes a Sample if set to "true", even if custom resources of its CRDs still exist.
+	forceDeleteAnnotation = "operator.kyma-project.io/force-delete"
+	// customResourcesPageSize is the number of custom resources listed per request
+	customResourcesPageSize = 500
+)
+
+// isDeletionBlocked reports whether the deletion of the Sample is blocked by custom resources of its CRDs,
+// unless it is forced by the force-delete annotation. It is checked for all Samples marked for deletion,
+// whatever their state. A blocked Sample is set to the DeletionBlockedState,
+// and the blocking objects are listed in the Deleting condition.
+func (r *SampleReconciler) isDeletionBlocked(ctx context.Context, objectInstance *v1alpha1.Sample,
+	status *v1alpha1.SampleStatus,
+) (bool, error) {
+	if objectInstance.GetAnnotations()[forceDeleteAnnotation] == "true" {
+		return false, nil
+	}
+	inventory, err := r.getDeletionInventory(ctx, objectInstance, status)
+	if errors.Is(err, errManifestUnavailable) {
+		// without inventory and manifest, no CRDs are known to be deleted
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	blocking, err := r.getBlockingObjects(ctx, objectInstance, inventory)
+	if err != nil || len(blocking) == 0 {
+		return false, err
+	}
+
+	message := fmt.Sprintf("deletion is blocked by custom resources of the CRDs of the manifest, "+
+		"delete them or set the %s annotation: %s", forceDeleteAnnotation, joinMessages(blocking))
+	condition := meta.FindStatusCondition(status.Conditions, v1alpha1.ConditionTypeDeleting)
+	if condition == nil || condition.Reason != v1alpha1.ConditionReasonDeletionBlocked {
+		r.Eventf(objectInstance, nil, "Warning", "DeletionBlocked", "Deleting", "%s", message)
+	}
+	status.WithState(r.getDeletionBlockedState()).
+		WithDeletingCondition(v1alpha1.ConditionReasonDeletionBlocked, message, objectInstance.GetGeneration())
+	return true, r.setStatusIfChanged(ctx, objectInstance, status)
+}
+
+// wasDeletionBlocked reports whether the status reports the deletion of the Sample as blocked.
+func wasDeletionBlocked(status *v1alpha1.SampleStatus) bool {
+	condition := meta.FindStatusCondition(status.Conditions, v1alpha1.ConditionTypeDeleting)
+	return condition != nil && condition.Reason == v1alpha1.ConditionReasonDeletionBlocked
+}
+
+func (r *SampleReconciler) getDeletionBlockedState() v1alpha1.State {
+	if r.DeletionBlockedState == "" {
+		return defaultDeletionBlockedState
+	}
+	return r.DeletionBlockedState
+}
+
+// getBlockingObjects returns the custom resources of the CRDs in the inventory which would be deleted together
+// with their CRD, except the ones in the inventory themselves. They were created by users, so the Sample must not
+// be deleted while they exist.
+func (r *SampleReconciler) getBlockingObjects(ctx context.Context, objectInstance *v1alpha1.Sample,
+	inventory []v1alpha1.InventoryEntry,
+) ([]string, error) {
+	inInventory := make(map[inventoryKey]bool, len(inventory))
+	for _, entry := range inventory {
+		inInventory[getInventoryKey(entry)] = true
+	}
+
+	blocking := make([]string, 0)
+	for _, entry := range inventory {
+		if entry.Group != crdGroupKind.Group || entry.Kind != crdGroupKind.Kind {
+			continue
+		}
+		crd := getObjectFromInventoryEntry(entry)
+		if err := r.Get(ctx, client.ObjectKeyFromObject(crd), crd); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return nil, fmt.Errorf("error while getting %s: %w", describeObject(crd), err)
+			}
+			continue
+		}
+		// CRDs which are kept do not delete their custom resources
+		if policy, err := getDeletionPolicy(crd, objectInstance.Spec.DeletionPolicy); err != nil ||
+			policy != v1alpha1.DeletionPolicyDelete {
+			continue
+		}
+
+		objs, err := r.listCustomResources(ctx, crd)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range objs {
+			if !inInventory[getInventoryKey(newInventoryEntry(obj))] {
+				blocking = append(blocking, describeObject(obj))
+			}
+		}
+	}
+	return blocking, nil
+}
+
+// listCustomResources lists all custom resources of the CRD in all namespaces, in its storage version,
+// page by page.
+func (r *SampleReconciler) listCustomResources(ctx context.Context, crd *unstructured.Unstructured,
+) ([]*unstructured.Unstructured, error) {
+	group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+	kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	version := ""
+	for _, v := range versions {
+		if v, ok := v.(map[string]any); ok && v["storage"] == true {
+			version, _ = v["name"].(string)
+		}
+	}
+
+	objs := make([]*unstructured.Unstructured, 0)
+	continueToken := ""
+	for {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(schema.GroupVersionKind{Group: group, Version: version, Kind: kind + "List"})
+		if err := r.List(ctx, list, client.Limit(customResourcesPageSize), client.Continue(continueToken)); err != nil {
+			// the CRD is not established (anymore), so there are no custom resources
+			if meta.IsNoMatchError(err) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("error while listing custom resources of %s: %w", describeObject(crd), err)
+		}
+		for i := range list.Items {
+			objs = append(objs, &list.Items[i])
+		}
+		if continueToken = list.GetContinue(); continueToken == "" {
+			return objs, nil
+		}
+	}
+}
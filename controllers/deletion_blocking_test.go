@@ -0,0 +1,72 @@
+package controllers_test
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sample CR is deleted while custom resources of its CRDs exist", Ordered, func() {
+	sampleCR := createSampleCR("deletion-blocking-sample", "./test/deletion-blocking")
+	sampleCRKey := client.ObjectKeyFromObject(sampleCR)
+	crdKey := client.ObjectKey{Name: "gadgets.blocking.kyma-project.io"}
+	gadget := &unstructured.Unstructured{}
+	gadget.SetGroupVersionKind(schema.GroupVersionKind{Group: "blocking.kyma-project.io", Version: "v1", Kind: "Gadget"})
+	gadget.SetNamespace(metav1.NamespaceDefault)
+	gadget.SetName("user-gadget")
+
+	It("should block the deletion and list only the custom resources which are not in the manifest", func() {
+		Expect(k8sClient.Create(ctx, sampleCR)).To(Succeed())
+		Eventually(getCRStatus(sampleCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Equal(CRStatus{State: v1alpha1.StateReady, InstallConditionStatus: metav1.ConditionTrue, Err: nil}))
+		Eventually(func() error {
+			return k8sClient.Create(ctx, gadget.DeepCopy())
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Succeed())
+
+		Expect(k8sClient.Delete(ctx, sampleCR)).To(Succeed())
+
+		Eventually(getCondition(sampleCRKey, v1alpha1.ConditionTypeDeleting)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(And(
+				HaveField("Reason", v1alpha1.ConditionReasonDeletionBlocked),
+				HaveField("Message", ContainSubstring("Gadget default/user-gadget")),
+				// the custom resources of the manifest are deleted with the Sample, so they do not block it
+				HaveField("Message", Not(ContainSubstring("sample-gadget"))),
+				HaveField("Message", Not(ContainSubstring("more"))),
+			))
+		Consistently(getCRStatus(sampleCRKey)).
+			WithTimeout(5 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(HaveField("State", v1alpha1.StateWarning))
+		Expect(k8sClient.Get(ctx, crdKey, getCRD())).To(Succeed())
+	})
+
+	It("should delete the resources once the deletion is forced", func() {
+		Expect(k8sClient.Get(ctx, sampleCRKey, sampleCR)).To(Succeed())
+		sampleCR.SetAnnotations(map[string]string{"operator.kyma-project.io/force-delete": "true"})
+		Expect(k8sClient.Update(ctx, sampleCR)).To(Succeed())
+
+		Eventually(func() bool {
+			return errors.IsNotFound(k8sClient.Get(ctx, crdKey, getCRD())) &&
+				errors.IsNotFound(k8sClient.Get(ctx, sampleCRKey, &v1alpha1.Sample{}))
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeTrue())
+	})
+})
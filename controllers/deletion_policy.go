@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+)
+
+// deletionPolicyAnnotation overrides the DeletionPolicy of the Sample for a single object, e.g. "retain".
+const deletionPolicyAnnotation = "operator.kyma-project.io/deletion-policy"
+
+var errInvalidDeletionPolicy = errors.New("invalid deletion policy")
+
+// getDeletionPolicy returns the DeletionPolicy of the object, which is the one of its deletion-policy annotation,
+// matched case-insensitively, or defaultPolicy if it is not annotated.
+func getDeletionPolicy(obj *unstructured.Unstructured, defaultPolicy v1alpha1.DeletionPolicy,
+) (v1alpha1.DeletionPolicy, error) {
+	value, ok := obj.GetAnnotations()[deletionPolicyAnnotation]
+	if !ok {
+		if defaultPolicy == "" {
+			return v1alpha1.DeletionPolicyDelete, nil
+		}
+		return defaultPolicy, nil
+	}
+	for _, policy := range []v1alpha1.DeletionPolicy{
+		v1alpha1.DeletionPolicyDelete, v1alpha1.DeletionPolicyOrphan, v1alpha1.DeletionPolicyRetain,
+	} {
+		if strings.EqualFold(value, string(policy)) {
+			return policy, nil
+		}
+	}
+	return "", fmt.Errorf("%w %q in annotation %s of %s", errInvalidDeletionPolicy, value,
+		deletionPolicyAnnotation, describeObject(obj))
+}
+
+// validateDeletionPolicies checks the deletion-policy annotations of the resources when they are applied,
+// so that an invalid value is reported before the resources have to be pruned or deleted.
+func validateDeletionPolicies(resources []*unstructured.Unstructured) error {
+	errs := make([]error, 0)
+	for _, obj := range resources {
+		if _, err := getDeletionPolicy(obj, v1alpha1.DeletionPolicyDelete); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deleteResource handles the object of the inventory entry according to its DeletionPolicy,
+// when the Sample is deleted or the object is pruned. Objects which no longer exist are ignored.
+func (r *SampleReconciler) deleteResource(ctx context.Context, objectInstance *v1alpha1.Sample,
+	entry v1alpha1.InventoryEntry,
+) error {
+	obj := getObjectFromInventoryEntry(entry)
+	if err := r.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	policy, err := getDeletionPolicy(obj, objectInstance.Spec.DeletionPolicy)
+	if err != nil {
+		return err
+	}
+
+	switch policy {
+	case v1alpha1.DeletionPolicyOrphan:
+		log.FromContext(ctx).Info("orphaning " + describeObject(obj))
+		return nil
+	case v1alpha1.DeletionPolicyRetain:
+		log.FromContext(ctx).Info("retaining " + describeObject(obj))
+		// the labels are removed, so the object is neither watched nor taken over by a new Sample
+		patch := fmt.Appendf(nil, `{"metadata":{"labels":{%q:null,%q:null}}}`, labelSampleName, labelSampleNamespace)
+		return client.IgnoreNotFound(r.Patch(ctx, obj, client.RawPatch(types.MergePatchType, patch)))
+	default:
+		if err := deleteObject(ctx, r.Client, obj); client.IgnoreNotFound(err) != nil {
+			recordObjectError(deleteErrorsTotal, obj, err)
+			return err
+		}
+		r.driftChecks.forget(obj)
+		return nil
+	}
+}
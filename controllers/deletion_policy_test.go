@@ -0,0 +1,88 @@
+package controllers_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sample CR is deleted with a deletion policy", Ordered, func() {
+	sampleCR := createSampleCR("deletion-policy-sample", "./test/deletion-policy")
+	sampleCR.Spec.DeletionPolicy = v1alpha1.DeletionPolicyOrphan
+	sampleCRKey := client.ObjectKeyFromObject(sampleCR)
+	deletedKey := client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "deletion-policy-deleted"}
+	retainedKey := client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "deletion-policy-retained"}
+	orphanedKey := client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "deletion-policy-orphaned"}
+
+	It("should keep the resources according to their deletion policy", func() {
+		Expect(k8sClient.Create(ctx, sampleCR)).To(Succeed())
+		Eventually(getCRStatus(sampleCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Equal(CRStatus{State: v1alpha1.StateReady, InstallConditionStatus: metav1.ConditionTrue, Err: nil}))
+
+		Expect(k8sClient.Delete(ctx, sampleCR)).To(Succeed())
+		Eventually(func() bool {
+			return errors.IsNotFound(k8sClient.Get(ctx, sampleCRKey, &v1alpha1.Sample{}))
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeTrue())
+
+		Expect(errors.IsNotFound(k8sClient.Get(ctx, deletedKey, &v1.ConfigMap{}))).To(BeTrue())
+
+		retained := &v1.ConfigMap{}
+		Expect(k8sClient.Get(ctx, retainedKey, retained)).To(Succeed())
+		Expect(retained.GetLabels()).NotTo(HaveKey("sample.kyma-project.io/name"))
+		Expect(retained.GetLabels()).NotTo(HaveKey("sample.kyma-project.io/namespace"))
+
+		orphaned := &v1.ConfigMap{}
+		Expect(k8sClient.Get(ctx, orphanedKey, orphaned)).To(Succeed())
+		Expect(orphaned.GetLabels()).To(HaveKeyWithValue("sample.kyma-project.io/name", sampleCR.GetName()))
+	})
+
+	It("should refuse to apply resources with an invalid deletion policy", func() {
+		invalidCR := createSampleCR("deletion-policy-invalid-sample", "")
+		invalidCR.Spec.ResourceFilePath = createTempManifestPath("deletion-policy-invalid")
+		manifest := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: deletion-policy-invalid
+  namespace: default
+  annotations:
+    operator.kyma-project.io/deletion-policy: keep
+`
+		Expect(os.WriteFile(filepath.Join(invalidCR.Spec.ResourceFilePath, "configmap.yaml"), []byte(manifest), 0o600)).
+			To(Succeed())
+		Expect(k8sClient.Create(ctx, invalidCR)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, invalidCR)
+
+		Eventually(getCondition(client.ObjectKeyFromObject(invalidCR), v1alpha1.ConditionTypeInstalled)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(And(
+				HaveField("Reason", v1alpha1.ConditionReasonManifestInvalid),
+				HaveField("Message", ContainSubstring(`invalid deletion policy "keep"`)),
+			))
+		Expect(errors.IsNotFound(k8sClient.Get(ctx, client.ObjectKey{
+			Namespace: metav1.NamespaceDefault, Name: "deletion-policy-invalid",
+		}, &v1.ConfigMap{}))).To(BeTrue())
+	})
+
+	AfterAll(func() {
+		for _, key := range []client.ObjectKey{retainedKey, orphanedKey} {
+			configMap := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}}
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, configMap))).To(Succeed())
+		}
+	})
+})
@@ -0,0 +1,402 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/structured-merge-diff/v6/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v6/value"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+)
+
+// appliedHashAnnotation records the hash of the manifest of an object when it was last applied,
+// to tell changes of the manifest from changes made by others.
+const (
+	appliedHashAnnotation = "operator.kyma-project.io/applied-hash"
+	// appliedHashLength is the number of bytes of the hash recorded in the annotation
+	appliedHashLength = 16
+)
+
+// fieldPath is the path of a field of an object, made of the keys of maps and the indices of lists.
+type fieldPath []any
+
+func (p fieldPath) String() string {
+	var builder strings.Builder
+	for _, segment := range p {
+		switch segment := segment.(type) {
+		case int:
+			fmt.Fprintf(&builder, "[%d]", segment)
+		default:
+			if builder.Len() > 0 {
+				builder.WriteString(".")
+			}
+			fmt.Fprintf(&builder, "%v", segment)
+		}
+	}
+	return builder.String()
+}
+
+// hasPrefix reports whether the path starts with the prefix.
+func (p fieldPath) hasPrefix(prefix fieldPath) bool {
+	return len(p) >= len(prefix) && slices.Equal(p[:len(prefix)], prefix)
+}
+
+// driftResult is the drift of a single applied object from the manifest.
+type driftResult struct {
+	obj *unstructured.Unstructured
+	// deleted is set if the object was deleted
+	deleted bool
+	// manifestChanged is set if the manifest of the object changed since it was last applied
+	manifestChanged bool
+	// fields are the paths of the fields of the manifest whose live values were changed by others
+	fields []fieldPath
+}
+
+func (d driftResult) String() string {
+	if d.deleted {
+		return describeObject(d.obj) + " was deleted"
+	}
+	fields := make([]string, 0, len(d.fields))
+	for _, field := range d.fields {
+		fields = append(fields, field.String())
+	}
+	return fmt.Sprintf("%s drifted in %s", describeObject(d.obj), strings.Join(fields, ", "))
+}
+
+// withoutDriftedFields returns a copy of the object without the drifted fields, so that applying it
+// keeps the values set by others. Fields of list elements identified by their index are removed with the list.
+func (d driftResult) withoutDriftedFields() *unstructured.Unstructured {
+	obj := d.obj.DeepCopy()
+	for _, field := range d.fields {
+		if index := slices.IndexFunc(field, func(segment any) bool {
+			_, isIndex := segment.(int)
+			return isIndex
+		}); index >= 0 {
+			field = field[:index]
+		}
+		keys := make([]string, 0, len(field))
+		for _, segment := range field {
+			keys = append(keys, fmt.Sprint(segment))
+		}
+		unstructured.RemoveNestedField(obj.Object, keys...)
+	}
+	return obj
+}
+
+// setAppliedHashes annotates the objects with the hash of their manifest.
+func setAppliedHashes(resources []*unstructured.Unstructured) error {
+	for _, obj := range resources {
+		hashed := obj.DeepCopy()
+		unstructured.RemoveNestedField(hashed.Object, "metadata", "annotations", appliedHashAnnotation)
+		manifest, err := json.Marshal(hashed.Object)
+		if err != nil {
+			return fmt.Errorf("error while hashing %s: %w", describeObject(obj), err)
+		}
+		annotations := obj.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string, 1)
+		}
+		hash := sha256.Sum256(manifest)
+		annotations[appliedHashAnnotation] = hex.EncodeToString(hash[:appliedHashLength])
+		obj.SetAnnotations(annotations)
+	}
+	return nil
+}
+
+// checkedVersion is the hash of the manifest and the resourceVersion of an object which did not drift.
+type checkedVersion struct {
+	hash            string
+	resourceVersion string
+}
+
+// driftChecks records the objects which did not drift when they were last applied, so that they are neither
+// checked for drift nor applied again until they or their manifest change.
+type driftChecks struct {
+	mu      sync.Mutex
+	checked map[inventoryKey]checkedVersion
+}
+
+func newDriftChecks() *driftChecks {
+	return &driftChecks{checked: make(map[inventoryKey]checkedVersion)}
+}
+
+// isUnchanged reports whether neither the live object nor the manifest of the object changed since
+// the object was recorded.
+func (c *driftChecks) isUnchanged(obj, live *unstructured.Unstructured) bool {
+	if live == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	checked, found := c.checked[getInventoryKey(newInventoryEntry(obj))]
+	return found && checked == getCheckedVersion(obj, live)
+}
+
+// record records the live object as not drifted, if it was applied with the current manifest.
+func (c *driftChecks) record(obj, live *unstructured.Unstructured) {
+	key := getInventoryKey(newInventoryEntry(obj))
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if live == nil || live.GetAnnotations()[appliedHashAnnotation] != obj.GetAnnotations()[appliedHashAnnotation] {
+		delete(c.checked, key)
+		return
+	}
+	c.checked[key] = getCheckedVersion(obj, live)
+}
+
+// forget removes the object, e.g. once it is deleted.
+func (c *driftChecks) forget(obj *unstructured.Unstructured) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.checked, getInventoryKey(newInventoryEntry(obj)))
+}
+
+func getCheckedVersion(obj, live *unstructured.Unstructured) checkedVersion {
+	return checkedVersion{
+		hash:            obj.GetAnnotations()[appliedHashAnnotation],
+		resourceVersion: live.GetResourceVersion(),
+	}
+}
+
+// getLiveObject returns the live state of the object, or nil if it does not exist.
+func (r *SampleReconciler) getLiveObject(ctx context.Context,
+	obj *unstructured.Unstructured,
+) (*unstructured.Unstructured, error) {
+	live := &unstructured.Unstructured{}
+	live.SetGroupVersionKind(obj.GroupVersionKind())
+	if err := r.Get(ctx, client.ObjectKeyFromObject(obj), live); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return nil, fmt.Errorf("error while getting %s: %w", describeObject(obj), err)
+		}
+		return nil, nil //nolint:nilnil // objects which do not exist yet have no live state
+	}
+	return live, nil
+}
+
+// detectDrift compares the live state of the object with the state it has after applying the manifest,
+// which is determined with a server-side dry-run. Objects which do not exist are only reported as drifted
+// if they are expected to exist. It returns nil if the object did not drift.
+//
+// If the manifest of the object did not change since it was last applied, every difference is a change
+// made by others. Otherwise, only the fields owned by other field managers drifted,
+// all other differences are changes of the manifest, which are applied.
+func (r *SampleReconciler) detectDrift(ctx context.Context, obj, live *unstructured.Unstructured,
+	expected bool,
+) (*driftResult, error) {
+	if live == nil {
+		if expected {
+			return &driftResult{obj: obj, deleted: true}, nil
+		}
+		return nil, nil //nolint:nilnil // objects which do not exist yet did not drift
+	}
+
+	applied, err := r.dryRunApply(ctx, obj)
+	if err != nil {
+		return nil, fmt.Errorf("error while detecting drift of %s: %w", describeObject(obj), err)
+	}
+	fields := getDriftedFields(obj, applied, live)
+	manifestChanged := obj.GetAnnotations()[appliedHashAnnotation] != live.GetAnnotations()[appliedHashAnnotation]
+	if manifestChanged {
+		foreign, err := getForeignFields(live)
+		if err != nil {
+			return nil, fmt.Errorf("error while detecting drift of %s: %w", describeObject(obj), err)
+		}
+		fields = slices.DeleteFunc(fields, func(field fieldPath) bool {
+			return !isForeignField(field, foreign)
+		})
+	}
+	if len(fields) > 0 {
+		return &driftResult{obj: obj, manifestChanged: manifestChanged, fields: fields}, nil
+	}
+	return nil, nil //nolint:nilnil // no drift detected
+}
+
+// dryRunApply returns the object as it is persisted by applying it, without modifying the cluster.
+func (r *SampleReconciler) dryRunApply(ctx context.Context,
+	obj *unstructured.Unstructured,
+) (*unstructured.Unstructured, error) {
+	applied := obj.DeepCopy()
+	applied.SetManagedFields(nil)
+	applied.SetResourceVersion("")
+	if err := r.Apply(ctx, client.ApplyConfigurationFromUnstructured(applied),
+		client.ForceOwnership, client.FieldOwner(fieldOwner), client.DryRunAll); err != nil {
+		return nil, fmt.Errorf("error while dry-run patching object: %w", err)
+	}
+	return applied, nil
+}
+
+// getDriftedFields returns the paths of all fields set in the desired object, whose values differ
+// between the applied and the live object. Of the metadata only labels and annotations are compared,
+// except for the hash of the manifest.
+func getDriftedFields(desired, applied, live *unstructured.Unstructured) []fieldPath {
+	fields := make([]fieldPath, 0)
+	for _, key := range slices.Sorted(maps.Keys(desired.Object)) {
+		switch key {
+		case "apiVersion", "kind", "status":
+			continue
+		case "metadata":
+			for _, metadataKey := range []string{"labels", "annotations"} {
+				collectDriftedFields(&fields, fieldPath{key, metadataKey},
+					getField(desired.Object, key, metadataKey),
+					getField(applied.Object, key, metadataKey),
+					getField(live.Object, key, metadataKey))
+			}
+		default:
+			collectDriftedFields(&fields, fieldPath{key}, desired.Object[key], applied.Object[key], live.Object[key])
+		}
+	}
+	hashField := fieldPath{"metadata", "annotations", appliedHashAnnotation}
+	return slices.DeleteFunc(fields, func(field fieldPath) bool { return slices.Equal(field, hashField) })
+}
+
+func collectDriftedFields(fields *[]fieldPath, path fieldPath, desired, applied, live any) {
+	switch desiredValue := desired.(type) {
+	case map[string]any:
+		appliedValue, _ := applied.(map[string]any)
+		liveValue, _ := live.(map[string]any)
+		for _, key := range slices.Sorted(maps.Keys(desiredValue)) {
+			collectDriftedFields(fields, slices.Concat(path, fieldPath{key}),
+				desiredValue[key], appliedValue[key], liveValue[key])
+		}
+	case []any:
+		appliedValue, _ := applied.([]any)
+		liveValue, _ := live.([]any)
+		if len(appliedValue) != len(liveValue) {
+			*fields = append(*fields, path)
+			return
+		}
+		for i := range min(len(desiredValue), len(appliedValue)) {
+			collectDriftedFields(fields, slices.Concat(path, fieldPath{i}),
+				desiredValue[i], appliedValue[i], liveValue[i])
+		}
+	default:
+		if !equality.Semantic.DeepEqual(applied, live) {
+			*fields = append(*fields, path)
+		}
+	}
+}
+
+// getForeignFields returns the paths of the fields of the live object, which are owned by field managers
+// other than the operator. Only the leaf fields of the managed fields are returned,
+// as their parents are usually shared by all managers.
+func getForeignFields(live *unstructured.Unstructured) ([]fieldPath, error) {
+	foreign := make([]fieldPath, 0)
+	for _, entry := range live.GetManagedFields() {
+		if entry.Manager == fieldOwner || entry.FieldsV1 == nil {
+			continue
+		}
+		set := &fieldpath.Set{}
+		if err := set.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+			return nil, fmt.Errorf("error while parsing managed fields of %s: %w", entry.Manager, err)
+		}
+		for path := range set.Leaves().All() {
+			if resolved, found := resolveFieldPath(live.Object, path); found {
+				foreign = append(foreign, resolved)
+			}
+		}
+	}
+	return foreign, nil
+}
+
+// resolveFieldPath returns the path of the managed field in the object, with the elements of lists,
+// which are identified by their keys or values in managed fields, resolved to their index.
+// It reports false if an element of a list does not exist in the object.
+func resolveFieldPath(obj map[string]any, path fieldpath.Path) (fieldPath, bool) {
+	resolved := make(fieldPath, 0, len(path))
+	var current any = obj
+	for _, element := range path {
+		switch {
+		case element.FieldName != nil:
+			currentMap, _ := current.(map[string]any)
+			current = currentMap[*element.FieldName]
+			resolved = append(resolved, *element.FieldName)
+		case element.Index != nil:
+			currentList, _ := current.([]any)
+			if *element.Index >= len(currentList) {
+				return nil, false
+			}
+			current = currentList[*element.Index]
+			resolved = append(resolved, *element.Index)
+		default:
+			currentList, _ := current.([]any)
+			index := slices.IndexFunc(currentList, func(item any) bool { return matchesElement(item, element) })
+			if index < 0 {
+				return nil, false
+			}
+			current = currentList[index]
+			resolved = append(resolved, index)
+		}
+	}
+	return resolved, true
+}
+
+// matchesElement reports whether the item of a list is the one selected by the keys or value of the element.
+func matchesElement(item any, element fieldpath.PathElement) bool {
+	if element.Value != nil {
+		return value.Equals(value.NewValueInterface(item), *element.Value)
+	}
+	itemMap, isMap := item.(map[string]any)
+	if element.Key == nil || !isMap {
+		return false
+	}
+	for _, key := range *element.Key {
+		if !value.Equals(value.NewValueInterface(itemMap[key.Name]), key.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// isForeignField reports whether the field, one of its parents or one of its children is owned by others.
+func isForeignField(field fieldPath, foreign []fieldPath) bool {
+	return slices.ContainsFunc(foreign, func(foreignField fieldPath) bool {
+		return field.hasPrefix(foreignField) || foreignField.hasPrefix(field)
+	})
+}
+
+func getField(obj map[string]any, fields ...string) any {
+	value, _, _ := unstructured.NestedFieldNoCopy(obj, fields...)
+	return value
+}
+
+// setDriftCondition reports the drift detected while applying the resources in the Drifted condition
+// and in the metrics.
+func (r *SampleReconciler) setDriftCondition(objectInstance *v1alpha1.Sample, status *v1alpha1.SampleStatus,
+	applier *resourceApplier,
+) {
+	recordDrifts(objectInstance, applier.drifts, applier.driftPolicy)
+	if len(applier.drifts) == 0 {
+		status.WithDriftCondition(metav1.ConditionFalse, v1alpha1.ConditionReasonNoDrift,
+			"no drift detected", objectInstance.GetGeneration())
+		return
+	}
+
+	messages := make([]string, 0, len(applier.drifts))
+	for _, drift := range applier.drifts {
+		messages = append(messages, drift.String())
+	}
+	message := joinMessages(messages)
+
+	if applier.driftPolicy == v1alpha1.DriftPolicyReport {
+		r.Eventf(objectInstance, nil, "Warning", v1alpha1.ConditionReasonDriftDetected, "Processing", "%s", message)
+		status.WithDriftCondition(metav1.ConditionTrue, v1alpha1.ConditionReasonDriftDetected,
+			message, objectInstance.GetGeneration())
+		return
+	}
+	r.Eventf(objectInstance, nil, "Normal", v1alpha1.ConditionReasonDriftRemediated, "Processing", "%s", message)
+	status.WithDriftCondition(metav1.ConditionFalse, v1alpha1.ConditionReasonDriftRemediated,
+		message, objectInstance.GetGeneration())
+}
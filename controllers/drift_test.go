@@ -0,0 +1,130 @@
+package controllers_test
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sample CR reports drift of its applied resources", Ordered, func() {
+	sampleCR := createSampleCR("drift-report-sample", "")
+	sampleCR.Spec.DriftPolicy = v1alpha1.DriftPolicyReport
+	sampleCRKey := client.ObjectKeyFromObject(sampleCR)
+	configKey := client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "drift-report-config"}
+
+	It("should report no drift after installation", func() {
+		sampleCR.Spec.ResourceFilePath = createConfigMapManifest(configKey.Name)
+		Expect(k8sClient.Create(ctx, sampleCR)).To(Succeed())
+
+		Eventually(getCRStatus(sampleCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Equal(CRStatus{State: v1alpha1.StateReady, InstallConditionStatus: metav1.ConditionTrue, Err: nil}))
+		Expect(getDriftCondition(sampleCRKey)(Default).Reason).To(Equal(v1alpha1.ConditionReasonNoDrift))
+	})
+
+	It("should report changed fields without reverting them", func() {
+		configMap := &v1.ConfigMap{}
+		Expect(k8sClient.Get(ctx, configKey, configMap)).To(Succeed())
+		configMap.Data["key"] = "drifted"
+		Expect(k8sClient.Update(ctx, configMap)).To(Succeed())
+
+		Eventually(getDriftCondition(sampleCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(And(
+				HaveField("Status", metav1.ConditionTrue),
+				HaveField("Reason", v1alpha1.ConditionReasonDriftDetected),
+				HaveField("Message", ContainSubstring("data.key")),
+			))
+		Consistently(func(g Gomega) string {
+			g.Expect(k8sClient.Get(ctx, configKey, configMap)).To(Succeed())
+			return configMap.Data["key"]
+		}).
+			WithTimeout(5 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Equal("drifted"))
+		// the drift left in place is not counted again with every reconciliation
+		Expect(getMetricValue("template_operator_drifted_objects", map[string]string{
+			"name": sampleCR.GetName(), "kind": "ConfigMap", "drift_policy": string(v1alpha1.DriftPolicyReport),
+		})(Default)).To(BeEquivalentTo(1))
+	})
+
+	It("should apply changes of the manifest and keep the drifted fields", func() {
+		writeConfigMapManifest(sampleCR.Spec.ResourceFilePath, configKey.Name,
+			map[string]string{"key": "value", "upgraded": "value"})
+		// changes of the manifest files are not watched, so the SampleCR is updated to reconcile it
+		Expect(k8sClient.Get(ctx, sampleCRKey, sampleCR)).To(Succeed())
+		sampleCR.SetLabels(map[string]string{"drift-test": "upgraded"})
+		Expect(k8sClient.Update(ctx, sampleCR)).To(Succeed())
+
+		Eventually(func(g Gomega) map[string]string {
+			configMap := &v1.ConfigMap{}
+			g.Expect(k8sClient.Get(ctx, configKey, configMap)).To(Succeed())
+			return configMap.Data
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Equal(map[string]string{"key": "drifted", "upgraded": "value"}))
+		Expect(getDriftCondition(sampleCRKey)(Default)).To(And(
+			HaveField("Reason", v1alpha1.ConditionReasonDriftDetected),
+			HaveField("Message", ContainSubstring("data.key")),
+			HaveField("Message", Not(ContainSubstring("data.upgraded"))),
+		))
+	})
+
+	It("should revert the drift once the DriftPolicy is set to Remediate", func() {
+		Expect(k8sClient.Get(ctx, sampleCRKey, sampleCR)).To(Succeed())
+		sampleCR.Spec.DriftPolicy = v1alpha1.DriftPolicyRemediate
+		Expect(k8sClient.Update(ctx, sampleCR)).To(Succeed())
+
+		Eventually(func(g Gomega) string {
+			configMap := &v1.ConfigMap{}
+			g.Expect(k8sClient.Get(ctx, configKey, configMap)).To(Succeed())
+			return configMap.Data["key"]
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Equal("value"))
+		Eventually(getDriftCondition(sampleCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(HaveField("Status", metav1.ConditionFalse))
+		Eventually(getMetricValue("template_operator_drifted_objects", map[string]string{
+			"name": sampleCR.GetName(), "drift_policy": string(v1alpha1.DriftPolicyReport),
+		})).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeZero())
+	})
+
+	It("should delete the resources with the SampleCR", func() {
+		Expect(k8sClient.Delete(ctx, sampleCR)).To(Succeed())
+		Eventually(func() bool {
+			return errors.IsNotFound(k8sClient.Get(ctx, configKey, &v1.ConfigMap{})) &&
+				errors.IsNotFound(k8sClient.Get(ctx, sampleCRKey, &v1alpha1.Sample{}))
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeTrue())
+	})
+})
+
+func getDriftCondition(sampleObjKey client.ObjectKey) func(g Gomega) metav1.Condition {
+	return func(gomega Gomega) metav1.Condition {
+		sampleCR := &v1alpha1.Sample{}
+		gomega.Expect(k8sClient.Get(ctx, sampleObjKey, sampleCR)).To(Succeed())
+		condition := meta.FindStatusCondition(sampleCR.Status.Conditions, v1alpha1.ConditionTypeDrifted)
+		gomega.Expect(condition).NotTo(BeNil())
+		return *condition
+	}
+}
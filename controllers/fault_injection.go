@@ -0,0 +1,352 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	errors2 "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// faultRulesKey is the key of the ConfigMap referenced by a FaultInjectingClient holding its rules.
+	faultRulesKey = "rules"
+	// faultRulesTTL is the time the rules of the ConfigMap are kept before it is read again.
+	faultRulesTTL = 5 * time.Second
+)
+
+var (
+	errInjectedFault    = errors.New("injected fault")
+	errInvalidFaultRule = errors.New("invalid fault rule")
+)
+
+// Fault is a failure injected into a call to the API server.
+type Fault string
+
+const (
+	// FaultConflict fails the call with a Conflict error, as if the object was changed concurrently.
+	FaultConflict Fault = "Conflict"
+	// FaultTimeout fails the call with a ServerTimeout error.
+	FaultTimeout Fault = "Timeout"
+	// FaultForbidden fails the call with a Forbidden error, as if the operator lacked the permission.
+	FaultForbidden Fault = "Forbidden"
+	// FaultLatency delays the call by the Latency of the rule, before it is sent to the API server.
+	FaultLatency Fault = "Latency"
+)
+
+// FaultRule injects a Fault into the calls matching its verbs and kind.
+type FaultRule struct {
+	Fault Fault `json:"fault"`
+	// Verbs are matched against get, list, create, update, patch, apply, delete and deleteAllOf,
+	// also for calls to subresources like status. All verbs are matched if empty.
+	Verbs []string `json:"verbs,omitempty"`
+	// Group of the kind, "*" matches all groups. Empty is the core group, unless Kind is empty as well.
+	Group string `json:"group,omitempty"`
+	// Kind is matched against the kind of the object, all kinds are matched if empty or "*".
+	Kind string `json:"kind,omitempty"`
+	// Probability between 0 and 1 with which a matching call fails, 1 if not set.
+	Probability float64 `json:"probability,omitempty"`
+	// Latency by which calls are delayed for a FaultLatency.
+	Latency metav1.Duration `json:"latency,omitempty"`
+}
+
+// ParseFaultRules parses a YAML or JSON list of FaultRules and checks that they are well-formed.
+func ParseFaultRules(data string) ([]FaultRule, error) {
+	var rules []FaultRule
+	if err := yaml.UnmarshalStrict([]byte(data), &rules); err != nil {
+		return nil, fmt.Errorf("error while parsing fault rules: %w", err)
+	}
+	for i, rule := range rules {
+		switch {
+		case !slices.Contains([]Fault{FaultConflict, FaultTimeout, FaultForbidden, FaultLatency}, rule.Fault):
+			return nil, fmt.Errorf("%w %d: unknown fault %q", errInvalidFaultRule, i, rule.Fault)
+		case rule.Probability < 0 || rule.Probability > 1:
+			return nil, fmt.Errorf("%w %d: probability %v is not between 0 and 1", errInvalidFaultRule, i,
+				rule.Probability)
+		case rule.Fault == FaultLatency && rule.Latency.Duration <= 0:
+			return nil, fmt.Errorf("%w %d: latency must be positive", errInvalidFaultRule, i)
+		}
+	}
+	return rules, nil
+}
+
+// FaultInjectingClient fails the calls of a client.Client according to FaultRules, to exercise the error
+// handling of the reconciler and of the clients watching the Samples, such as Lifecycle Manager.
+type FaultInjectingClient struct {
+	client.Client
+
+	// Rules are applied to every call.
+	Rules []FaultRule
+	// ConfigMap references a ConfigMap holding further rules as a YAML list in its "rules" key.
+	// It is read again once its rules are older than faultRulesTTL, so faults can be changed while the operator
+	// is running.
+	ConfigMap *client.ObjectKey
+	// Reader reads the ConfigMap without a cache, e.g. the API reader of the manager,
+	// so that no informer is started for all ConfigMaps of the cluster.
+	Reader client.Reader
+
+	mu             sync.Mutex
+	configMapRules []FaultRule
+	configMapRead  time.Time
+}
+
+// NewFaultInjectingClient wraps the client, so that its calls fail according to the rules and the rules
+// of the ConfigMap, if set, which is read with the reader.
+func NewFaultInjectingClient(c client.Client, reader client.Reader, rules []FaultRule, configMap *client.ObjectKey,
+) *FaultInjectingClient {
+	return &FaultInjectingClient{Client: c, Reader: reader, Rules: rules, ConfigMap: configMap}
+}
+
+func (c *FaultInjectingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object,
+	opts ...client.GetOption,
+) error {
+	if err := c.injectFault(ctx, "get", obj, key.Name); err != nil {
+		return err
+	}
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func (c *FaultInjectingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if err := c.injectFault(ctx, "list", list, ""); err != nil {
+		return err
+	}
+	return c.Client.List(ctx, list, opts...)
+}
+
+func (c *FaultInjectingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if err := c.injectFault(ctx, "create", obj, obj.GetName()); err != nil {
+		return err
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *FaultInjectingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if err := c.injectFault(ctx, "update", obj, obj.GetName()); err != nil {
+		return err
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *FaultInjectingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch,
+	opts ...client.PatchOption,
+) error {
+	if err := c.injectFault(ctx, "patch", obj, obj.GetName()); err != nil {
+		return err
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *FaultInjectingClient) Apply(ctx context.Context, obj runtime.ApplyConfiguration,
+	opts ...client.ApplyOption,
+) error {
+	if err := c.injectApplyFault(ctx, obj); err != nil {
+		return err
+	}
+	return c.Client.Apply(ctx, obj, opts...)
+}
+
+func (c *FaultInjectingClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	if err := c.injectFault(ctx, "delete", obj, obj.GetName()); err != nil {
+		return err
+	}
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *FaultInjectingClient) DeleteAllOf(ctx context.Context, obj client.Object,
+	opts ...client.DeleteAllOfOption,
+) error {
+	if err := c.injectFault(ctx, "deleteAllOf", obj, ""); err != nil {
+		return err
+	}
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
+
+//nolint:ireturn // implements client.StatusClient
+func (c *FaultInjectingClient) Status() client.SubResourceWriter {
+	return c.SubResource("status")
+}
+
+//nolint:ireturn // implements client.SubResourceClientConstructor
+func (c *FaultInjectingClient) SubResource(subResource string) client.SubResourceClient {
+	return &faultInjectingSubResourceClient{SubResourceClient: c.Client.SubResource(subResource), parent: c}
+}
+
+// faultInjectingSubResourceClient fails the calls to a subresource according to the rules of its parent.
+type faultInjectingSubResourceClient struct {
+	client.SubResourceClient
+
+	parent *FaultInjectingClient
+}
+
+func (c *faultInjectingSubResourceClient) Get(ctx context.Context, obj, subResource client.Object,
+	opts ...client.SubResourceGetOption,
+) error {
+	if err := c.parent.injectFault(ctx, "get", obj, obj.GetName()); err != nil {
+		return err
+	}
+	return c.SubResourceClient.Get(ctx, obj, subResource, opts...)
+}
+
+func (c *faultInjectingSubResourceClient) Create(ctx context.Context, obj, subResource client.Object,
+	opts ...client.SubResourceCreateOption,
+) error {
+	if err := c.parent.injectFault(ctx, "create", obj, obj.GetName()); err != nil {
+		return err
+	}
+	return c.SubResourceClient.Create(ctx, obj, subResource, opts...)
+}
+
+func (c *faultInjectingSubResourceClient) Update(ctx context.Context, obj client.Object,
+	opts ...client.SubResourceUpdateOption,
+) error {
+	if err := c.parent.injectFault(ctx, "update", obj, obj.GetName()); err != nil {
+		return err
+	}
+	return c.SubResourceClient.Update(ctx, obj, opts...)
+}
+
+func (c *faultInjectingSubResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch,
+	opts ...client.SubResourcePatchOption,
+) error {
+	if err := c.parent.injectFault(ctx, "patch", obj, obj.GetName()); err != nil {
+		return err
+	}
+	return c.SubResourceClient.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *faultInjectingSubResourceClient) Apply(ctx context.Context, obj runtime.ApplyConfiguration,
+	opts ...client.SubResourceApplyOption,
+) error {
+	if err := c.parent.injectApplyFault(ctx, obj); err != nil {
+		return err
+	}
+	return c.SubResourceClient.Apply(ctx, obj, opts...)
+}
+
+// injectApplyFault injects a fault into the apply of an apply configuration, whose kind and name are only
+// known from its serialized form.
+func (c *FaultInjectingClient) injectApplyFault(ctx context.Context, obj runtime.ApplyConfiguration) error {
+	applied := struct {
+		metav1.TypeMeta `json:",inline"`
+		Metadata        struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+	}{}
+	if data, err := json.Marshal(obj); err == nil {
+		_ = json.Unmarshal(data, &applied)
+	}
+	return c.injectGVKFault(ctx, "apply", applied.GroupVersionKind(), applied.Metadata.Name)
+}
+
+// injectFault injects the fault of the first rule matching the verb and the kind of the object, if any.
+func (c *FaultInjectingClient) injectFault(ctx context.Context, verb string, obj runtime.Object, name string) error {
+	gvk, err := c.GroupVersionKindFor(obj)
+	if err != nil {
+		gvk = obj.GetObjectKind().GroupVersionKind()
+	}
+	if _, isList := obj.(client.ObjectList); isList {
+		gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	}
+	return c.injectGVKFault(ctx, verb, gvk, name)
+}
+
+func (c *FaultInjectingClient) injectGVKFault(ctx context.Context, verb string, gvk schema.GroupVersionKind,
+	name string,
+) error {
+	for _, rule := range c.getRules(ctx) {
+		if !rule.matches(verb, gvk) {
+			continue
+		}
+		//nolint:gosec // the faults are random, they do not need a cryptographically secure source
+		if rule.Probability > 0 && rand.Float64() >= rule.Probability {
+			continue
+		}
+		log.FromContext(ctx).V(1).Info(fmt.Sprintf("injecting %s into %s of %s %s", rule.Fault, verb, gvk, name))
+		return c.newFaultError(ctx, rule, verb, gvk, name)
+	}
+	return nil
+}
+
+// getRules returns the Rules of the client and the ones of its ConfigMap.
+func (c *FaultInjectingClient) getRules(ctx context.Context) []FaultRule {
+	if c.ConfigMap == nil {
+		return c.Rules
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if time.Since(c.configMapRead) >= faultRulesTTL {
+		c.configMapRules = c.readConfigMapRules(ctx)
+		c.configMapRead = time.Now()
+	}
+	return append(slices.Clone(c.Rules), c.configMapRules...)
+}
+
+// readConfigMapRules reads the rules of the ConfigMap.
+// Rules of a ConfigMap which does not exist or is malformed are ignored.
+func (c *FaultInjectingClient) readConfigMapRules(ctx context.Context) []FaultRule {
+	configMap := &corev1.ConfigMap{}
+	if err := c.Reader.Get(ctx, *c.ConfigMap, configMap); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			log.FromContext(ctx).Error(err, "error while getting fault injection ConfigMap")
+		}
+		return nil
+	}
+	rules, err := ParseFaultRules(configMap.Data[faultRulesKey])
+	if err != nil {
+		log.FromContext(ctx).Error(err, "error while parsing fault injection ConfigMap")
+		return nil
+	}
+	return rules
+}
+
+// newFaultError returns the error of the fault, or nil once the latency of a FaultLatency passed.
+func (c *FaultInjectingClient) newFaultError(ctx context.Context, rule FaultRule, verb string,
+	gvk schema.GroupVersionKind, name string,
+) error {
+	resource := schema.GroupResource{Group: gvk.Group, Resource: strings.ToLower(gvk.Kind)}
+	if mapping, err := c.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err == nil {
+		resource = mapping.Resource.GroupResource()
+	}
+	switch rule.Fault {
+	case FaultConflict:
+		return errors2.NewConflict(resource, name, errInjectedFault)
+	case FaultTimeout:
+		return errors2.NewServerTimeout(resource, verb, 1)
+	case FaultForbidden:
+		return errors2.NewForbidden(resource, name, errInjectedFault)
+	default:
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("error while injecting latency: %w", ctx.Err())
+		case <-time.After(rule.Latency.Duration):
+			return nil
+		}
+	}
+}
+
+func (rule *FaultRule) matches(verb string, gvk schema.GroupVersionKind) bool {
+	if len(rule.Verbs) > 0 && !slices.Contains(rule.Verbs, verb) {
+		return false
+	}
+	if rule.Kind != "" && rule.Kind != "*" && rule.Kind != gvk.Kind {
+		return false
+	}
+	// an empty group only matches the core group if a kind is set
+	if rule.Group == "*" || (rule.Group == "" && (rule.Kind == "" || rule.Kind == "*")) {
+		return true
+	}
+	return rule.Group == gvk.Group
+}
@@ -0,0 +1,75 @@
+package controllers_test
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/template-operator/controllers"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Faults are injected into the calls of a client", Ordered, func() {
+	rulesConfigMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "fault-injection-rules", Namespace: metav1.NamespaceDefault},
+	}
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "fault-injection-config", Namespace: metav1.NamespaceDefault},
+		Data:       map[string]string{"key": "value"},
+	}
+	var faultyClient client.Client
+
+	BeforeAll(func() {
+		rules, err := controllers.ParseFaultRules(`
+- fault: Forbidden
+  verbs: [delete]
+  kind: ConfigMap
+- fault: Latency
+  verbs: [get]
+  kind: ConfigMap
+  latency: 1s`)
+		Expect(err).NotTo(HaveOccurred())
+		faultyClient = controllers.NewFaultInjectingClient(k8sClient, k8sClient, rules,
+			ptr.To(client.ObjectKeyFromObject(rulesConfigMap)))
+		Expect(k8sClient.Create(ctx, configMap)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, configMap)
+	})
+
+	It("should inject the faults of the rules", func() {
+		Expect(errors.IsForbidden(faultyClient.Delete(ctx, configMap))).To(BeTrue())
+
+		start := time.Now()
+		Expect(faultyClient.Get(ctx, client.ObjectKeyFromObject(configMap), &v1.ConfigMap{})).To(Succeed())
+		Expect(time.Since(start)).To(BeNumerically(">=", time.Second))
+	})
+
+	It("should not inject faults into calls of other kinds", func() {
+		Expect(faultyClient.List(ctx, &v1.SecretList{})).To(Succeed())
+	})
+
+	It("should inject the faults of the rules of the ConfigMap", func() {
+		rulesConfigMap.Data = map[string]string{"rules": "[{fault: Conflict, verbs: [apply, update], kind: ConfigMap}]"}
+		Expect(k8sClient.Create(ctx, rulesConfigMap)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, rulesConfigMap)
+
+		Eventually(func() bool {
+			return errors.IsConflict(faultyClient.Update(ctx, configMap))
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeTrue())
+	})
+
+	It("should reject malformed rules", func() {
+		_, err := controllers.ParseFaultRules("[{fault: Crash}]")
+		Expect(err).To(MatchError(ContainSubstring("unknown fault")))
+		_, err = controllers.ParseFaultRules("[{fault: Timeout, probability: 2}]")
+		Expect(err).To(MatchError(ContainSubstring("probability")))
+	})
+})
@@ -0,0 +1,312 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+)
+
+const (
+	conditionTrue  = "True"
+	conditionFalse = "False"
+	// maxConditionMessages limits the number of objects listed in a condition message.
+	maxConditionMessages = 5
+)
+
+// healthResult is the health of a single object applied from the manifest.
+type healthResult struct {
+	obj *unstructured.Unstructured
+	// generation of the live object
+	generation int64
+	health     v1alpha1.Health
+	message    string
+}
+
+type healthEvaluator func(obj *unstructured.Unstructured) (v1alpha1.Health, string)
+
+//nolint:gochecknoglobals // immutable registry of built-in health evaluations
+var healthEvaluators = map[schema.GroupKind]healthEvaluator{
+	{Group: "apps", Kind: "Deployment"}:  evaluateDeploymentHealth,
+	{Group: "apps", Kind: "StatefulSet"}: evaluateStatefulSetHealth,
+	{Group: "apps", Kind: "DaemonSet"}:   evaluateDaemonSetHealth,
+	{Group: "", Kind: "Pod"}:             evaluatePodHealth,
+	{Group: "batch", Kind: "Job"}:        evaluateJobHealth,
+	crdGroupKind:                         evaluateCRDHealth,
+}
+
+// checkResourcesHealth fetches the live state of the given objects and evaluates their health.
+func checkResourcesHealth(ctx context.Context, reader client.Reader, objs []*unstructured.Unstructured,
+	customChecks []v1alpha1.CustomHealthCheck,
+) ([]healthResult, error) {
+	results := make([]healthResult, 0, len(objs))
+	for _, obj := range objs {
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(obj.GroupVersionKind())
+		if err := reader.Get(ctx, client.ObjectKeyFromObject(obj), live); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return nil, fmt.Errorf("error while checking health of %s: %w", describeObject(obj), err)
+			}
+			results = append(results, healthResult{obj: obj, health: v1alpha1.HealthProgressing, message: "not found"})
+			continue
+		}
+		health, message := evaluateHealth(live, customChecks)
+		results = append(results, healthResult{
+			obj: obj, generation: live.GetGeneration(), health: health, message: message,
+		})
+	}
+	return results, nil
+}
+
+// aggregateHealth returns the worst health of all results,
+// together with a message listing the objects which are not healthy.
+func aggregateHealth(results []healthResult) (v1alpha1.Health, string) {
+	health := v1alpha1.HealthHealthy
+	messages := make([]string, 0)
+	for _, result := range results {
+		if result.health == v1alpha1.HealthHealthy {
+			continue
+		}
+		if health != v1alpha1.HealthDegraded {
+			health = result.health
+		}
+		messages = append(messages, fmt.Sprintf("%s is %s: %s", describeObject(result.obj), result.health,
+			result.message))
+	}
+	if len(messages) == 0 {
+		return health, "all resources are healthy"
+	}
+	return health, joinMessages(messages)
+}
+
+// joinMessages joins the messages about single objects into a condition message,
+// listing at most maxConditionMessages of them.
+func joinMessages(messages []string) string {
+	if len(messages) > maxConditionMessages {
+		messages = append(messages[:maxConditionMessages:maxConditionMessages],
+			fmt.Sprintf("and %d more", len(messages)-maxConditionMessages))
+	}
+	return strings.Join(messages, "; ")
+}
+
+// evaluateHealth evaluates the health of the live object. Kinds without a built-in evaluation are checked
+// against the matching custom health check, and otherwise by their observedGeneration and well-known
+// Ready, Reconciling and Stalled conditions. Objects without any status are healthy once they exist.
+func evaluateHealth(obj *unstructured.Unstructured,
+	customChecks []v1alpha1.CustomHealthCheck,
+) (v1alpha1.Health, string) {
+	groupKind := obj.GroupVersionKind().GroupKind()
+	for _, check := range customChecks {
+		if check.Group == groupKind.Group && check.Kind == groupKind.Kind {
+			return evaluateCustomHealth(obj, check)
+		}
+	}
+	if evaluator, found := healthEvaluators[groupKind]; found {
+		return evaluator(obj)
+	}
+	return evaluateGenericHealth(obj)
+}
+
+func evaluateCustomHealth(obj *unstructured.Unstructured, check v1alpha1.CustomHealthCheck) (v1alpha1.Health, string) {
+	if check.DegradedConditionType != "" {
+		if status, message := getCondition(obj, check.DegradedConditionType); status == conditionTrue {
+			return v1alpha1.HealthDegraded, message
+		}
+	}
+	if !isGenerationObserved(obj) {
+		return v1alpha1.HealthProgressing, "waiting for the latest generation to be observed"
+	}
+	if status, _ := getCondition(obj, check.ConditionType); status != conditionTrue {
+		return v1alpha1.HealthProgressing, fmt.Sprintf("waiting for condition %s to be True", check.ConditionType)
+	}
+	return v1alpha1.HealthHealthy, ""
+}
+
+func evaluateGenericHealth(obj *unstructured.Unstructured) (v1alpha1.Health, string) {
+	if status, message := getCondition(obj, "Stalled"); status == conditionTrue {
+		return v1alpha1.HealthDegraded, message
+	}
+	if !isGenerationObserved(obj) {
+		return v1alpha1.HealthProgressing, "waiting for the latest generation to be observed"
+	}
+	if status, message := getCondition(obj, "Reconciling"); status == conditionTrue {
+		return v1alpha1.HealthProgressing, message
+	}
+	if status, message := getCondition(obj, "Ready"); status == conditionFalse {
+		return v1alpha1.HealthProgressing, message
+	}
+	return v1alpha1.HealthHealthy, ""
+}
+
+func evaluateDeploymentHealth(obj *unstructured.Unstructured) (v1alpha1.Health, string) {
+	if !isGenerationObserved(obj) {
+		return v1alpha1.HealthProgressing, "waiting for the rollout to be observed"
+	}
+	if status, reason := getConditionWithReason(obj, "Progressing"); status == conditionFalse &&
+		reason == "ProgressDeadlineExceeded" {
+		return v1alpha1.HealthDegraded, "rollout exceeded its progress deadline"
+	}
+	replicas := getInt(obj, 1, "spec", "replicas")
+	updated := getInt(obj, 0, "status", "updatedReplicas")
+	current := getInt(obj, 0, "status", "replicas")
+	available := getInt(obj, 0, "status", "availableReplicas")
+	switch {
+	case updated < replicas:
+		return v1alpha1.HealthProgressing, fmt.Sprintf("%d of %d replicas updated", updated, replicas)
+	case current > updated:
+		return v1alpha1.HealthProgressing, fmt.Sprintf("%d old replicas pending termination", current-updated)
+	case available < updated:
+		return v1alpha1.HealthProgressing, fmt.Sprintf("%d of %d updated replicas available", available, updated)
+	}
+	return v1alpha1.HealthHealthy, ""
+}
+
+func evaluateStatefulSetHealth(obj *unstructured.Unstructured) (v1alpha1.Health, string) {
+	if !isGenerationObserved(obj) {
+		return v1alpha1.HealthProgressing, "waiting for the rollout to be observed"
+	}
+	replicas := getInt(obj, 1, "spec", "replicas")
+	ready := getInt(obj, 0, "status", "readyReplicas")
+	if ready < replicas {
+		return v1alpha1.HealthProgressing, fmt.Sprintf("%d of %d replicas ready", ready, replicas)
+	}
+	strategy, _, _ := unstructured.NestedString(obj.Object, "spec", "updateStrategy", "type")
+	if strategy == "OnDelete" {
+		return v1alpha1.HealthHealthy, ""
+	}
+	partition := getInt(obj, 0, "spec", "updateStrategy", "rollingUpdate", "partition")
+	updated := getInt(obj, 0, "status", "updatedReplicas")
+	if updated < replicas-partition {
+		return v1alpha1.HealthProgressing, fmt.Sprintf("%d of %d replicas updated", updated, replicas-partition)
+	}
+	currentRevision, _, _ := unstructured.NestedString(obj.Object, "status", "currentRevision")
+	updateRevision, _, _ := unstructured.NestedString(obj.Object, "status", "updateRevision")
+	if partition == 0 && currentRevision != updateRevision {
+		return v1alpha1.HealthProgressing, "waiting for the rolling update to complete"
+	}
+	return v1alpha1.HealthHealthy, ""
+}
+
+func evaluateDaemonSetHealth(obj *unstructured.Unstructured) (v1alpha1.Health, string) {
+	if !isGenerationObserved(obj) {
+		return v1alpha1.HealthProgressing, "waiting for the rollout to be observed"
+	}
+	desired := getInt(obj, 0, "status", "desiredNumberScheduled")
+	updated := getInt(obj, 0, "status", "updatedNumberScheduled")
+	available := getInt(obj, 0, "status", "numberAvailable")
+	switch {
+	case updated < desired:
+		return v1alpha1.HealthProgressing, fmt.Sprintf("%d of %d pods updated", updated, desired)
+	case available < desired:
+		return v1alpha1.HealthProgressing, fmt.Sprintf("%d of %d pods available", available, desired)
+	}
+	return v1alpha1.HealthHealthy, ""
+}
+
+func evaluatePodHealth(obj *unstructured.Unstructured) (v1alpha1.Health, string) {
+	phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+	switch phase {
+	case "Succeeded":
+		return v1alpha1.HealthHealthy, ""
+	case "Failed":
+		return v1alpha1.HealthDegraded, "pod failed"
+	}
+	containerStatuses, _, _ := unstructured.NestedSlice(obj.Object, "status", "containerStatuses")
+	for _, containerStatus := range containerStatuses {
+		containerStatusMap, ok := containerStatus.(map[string]any)
+		if !ok {
+			continue
+		}
+		reason, _, _ := unstructured.NestedString(containerStatusMap, "state", "waiting", "reason")
+		switch reason {
+		case "CrashLoopBackOff", "ImagePullBackOff", "ErrImagePull", "InvalidImageName", "CreateContainerConfigError":
+			return v1alpha1.HealthDegraded, "container is waiting with reason " + reason
+		}
+	}
+	if status, _ := getCondition(obj, "Ready"); status != conditionTrue {
+		return v1alpha1.HealthProgressing, "pod is not ready"
+	}
+	return v1alpha1.HealthHealthy, ""
+}
+
+func evaluateJobHealth(obj *unstructured.Unstructured) (v1alpha1.Health, string) {
+	if status, message := getCondition(obj, "Failed"); status == conditionTrue {
+		return v1alpha1.HealthDegraded, message
+	}
+	if status, _ := getCondition(obj, "Complete"); status == conditionTrue {
+		return v1alpha1.HealthHealthy, ""
+	}
+	return v1alpha1.HealthProgressing, "job has not completed"
+}
+
+func evaluateCRDHealth(obj *unstructured.Unstructured) (v1alpha1.Health, string) {
+	if status, message := getCondition(obj, "NamesAccepted"); status == conditionFalse {
+		return v1alpha1.HealthDegraded, message
+	}
+	if status, _ := getCondition(obj, "Established"); status != conditionTrue {
+		return v1alpha1.HealthProgressing, "waiting for the CRD to be established"
+	}
+	return v1alpha1.HealthHealthy, ""
+}
+
+// isGenerationObserved reports whether the status of obj reflects its latest generation.
+// Objects which do not report an observedGeneration are considered up to date.
+func isGenerationObserved(obj *unstructured.Unstructured) bool {
+	observedGeneration, found, err := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if !found || err != nil {
+		return true
+	}
+	return observedGeneration >= obj.GetGeneration()
+}
+
+// getCondition returns the status and message of the status condition of the given type.
+func getCondition(obj *unstructured.Unstructured, conditionType string) (string, string) {
+	condition := findCondition(obj, conditionType)
+	status, _, _ := unstructured.NestedString(condition, "status")
+	message, _, _ := unstructured.NestedString(condition, "message")
+	return status, message
+}
+
+// getConditionWithReason returns the status and reason of the status condition of the given type.
+func getConditionWithReason(obj *unstructured.Unstructured, conditionType string) (string, string) {
+	condition := findCondition(obj, conditionType)
+	status, _, _ := unstructured.NestedString(condition, "status")
+	reason, _, _ := unstructured.NestedString(condition, "reason")
+	return status, reason
+}
+
+func findCondition(obj *unstructured.Unstructured, conditionType string) map[string]any {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, condition := range conditions {
+		conditionMap, ok := condition.(map[string]any)
+		if !ok {
+			continue
+		}
+		if conditionMap["type"] == conditionType {
+			return conditionMap
+		}
+	}
+	return nil
+}
+
+func getInt(obj *unstructured.Unstructured, defaultValue int64, fields ...string) int64 {
+	value, found, err := unstructured.NestedInt64(obj.Object, fields...)
+	if !found || err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// describeObject returns a human-readable reference of the object, e.g. Deployment default/redis.
+func describeObject(obj client.Object) string {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if obj.GetNamespace() == "" {
+		return kind + " " + obj.GetName()
+	}
+	return kind + " " + obj.GetNamespace() + "/" + obj.GetName()
+}
@@ -0,0 +1,117 @@
+package controllers_test
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const healthPodName = "health-pod"
+
+var _ = Describe("Sample CR waits for its resources to become healthy", Ordered, func() {
+	sampleCR := createSampleCR("health-sample", "./test/health")
+	sampleCRKey := client.ObjectKeyFromObject(sampleCR)
+
+	It("should stay in Processing state while the pod is not ready", func() {
+		Expect(k8sClient.Create(ctx, sampleCR)).To(Succeed())
+
+		Eventually(getHealthReason(sampleCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Equal(string(v1alpha1.HealthProgressing)))
+		Consistently(getCRStatus(sampleCRKey)).
+			WithTimeout(5 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Equal(CRStatus{
+				State:                  v1alpha1.StateProcessing,
+				InstallConditionStatus: metav1.ConditionUnknown, Err: nil,
+			}))
+	})
+
+	It("should set state to Error once the pod is not ready for longer than the HealthCheckTimeout", func() {
+		// the pod has been unhealthy since long before the HealthCheckTimeout of the reconciler
+		longAgo := metav1.NewTime(time.Now().Add(-time.Hour))
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(ctx, sampleCRKey, sampleCR)).To(Succeed())
+			condition := meta.FindStatusCondition(sampleCR.Status.Conditions, v1alpha1.ConditionTypeHealthy)
+			g.Expect(condition).NotTo(BeNil())
+			condition.LastTransitionTime = longAgo
+			sampleCR.Status.GenerationObservedTime = &longAgo
+			g.Expect(k8sClient.Status().Update(ctx, sampleCR)).To(Succeed())
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Succeed())
+
+		Eventually(getCondition(sampleCRKey, v1alpha1.ConditionTypeInstalled)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(HaveField("Reason", v1alpha1.ConditionReasonHealthCheckTimeout))
+		Expect(getCRStatus(sampleCRKey)(Default).State).To(Equal(v1alpha1.StateError))
+	})
+
+	It("should restart the HealthCheckTimeout for a new generation", func() {
+		Expect(k8sClient.Get(ctx, sampleCRKey, sampleCR)).To(Succeed())
+		sampleCR.Spec.FinalState = v1alpha1.StateReady
+		Expect(k8sClient.Update(ctx, sampleCR)).To(Succeed())
+
+		Eventually(getCRStatus(sampleCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Equal(CRStatus{
+				State:                  v1alpha1.StateProcessing,
+				InstallConditionStatus: metav1.ConditionUnknown, Err: nil,
+			}))
+		Consistently(getCRStatus(sampleCRKey)).
+			WithTimeout(5 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(HaveField("State", v1alpha1.StateProcessing))
+		Expect(getCondition(sampleCRKey, v1alpha1.ConditionTypeHealthy)(Default).LastTransitionTime.Time).
+			To(BeTemporally("<", time.Now().Add(-time.Minute)))
+	})
+
+	It("should set state to Ready once the pod is ready", func() {
+		Eventually(getPod(metav1.NamespaceDefault, healthPodName)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeTrue())
+
+		Eventually(getCRStatus(sampleCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Equal(CRStatus{State: v1alpha1.StateReady, InstallConditionStatus: metav1.ConditionTrue, Err: nil}))
+		Expect(getHealthReason(sampleCRKey)(Default)).To(Equal(string(v1alpha1.HealthHealthy)))
+	})
+
+	It("should delete the resources with the SampleCR", func() {
+		Expect(k8sClient.Delete(ctx, sampleCR)).To(Succeed())
+		Eventually(func() bool {
+			err := k8sClient.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: healthPodName}, &v1.Pod{})
+			return errors.IsNotFound(err) && errors.IsNotFound(k8sClient.Get(ctx, sampleCRKey, &v1alpha1.Sample{}))
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeTrue())
+	})
+})
+
+func getHealthReason(sampleObjKey client.ObjectKey) func(g Gomega) string {
+	return func(gomega Gomega) string {
+		sampleCR := &v1alpha1.Sample{}
+		gomega.Expect(k8sClient.Get(ctx, sampleObjKey, sampleCR)).To(Succeed())
+		condition := meta.FindStatusCondition(sampleCR.Status.Conditions, v1alpha1.ConditionTypeHealthy)
+		if condition == nil {
+			return ""
+		}
+		return condition.Reason
+	}
+}
@@ -0,0 +1,127 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+)
+
+const (
+	helmNotesFile   = "NOTES.txt"
+	crdScopeCluster = "Cluster"
+)
+
+var errInvalidHelmValues = errors.New("invalid inline helm values")
+
+//nolint:gochecknoglobals // immutable group kind of CustomResourceDefinitions
+var crdGroupKind = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+
+// renderHelmChart renders the chart referenced by the HelmSource in-process, using the given release name
+// and namespace, and returns the resulting multi-document manifest. CRDs from the chart's crds directory
+// are placed first, followed by the rendered templates in lexical order of their file names.
+func renderHelmChart(source *v1alpha1.HelmSource, releaseName, namespace string) (string, error) {
+	chrt, err := loader.Load(source.ChartPath)
+	if err != nil {
+		return "", fmt.Errorf("error loading helm chart %s: %w", source.ChartPath, err)
+	}
+
+	vals, err := getHelmValues(source)
+	if err != nil {
+		return "", err
+	}
+
+	renderValues, err := chartutil.ToRenderValues(chrt, vals, chartutil.ReleaseOptions{
+		Name:      releaseName,
+		Namespace: namespace,
+		Revision:  1,
+		IsInstall: true,
+	}, nil)
+	if err != nil {
+		return "", fmt.Errorf("error preparing values of helm chart %s: %w", source.ChartPath, err)
+	}
+
+	rendered, err := engine.Render(chrt, renderValues)
+	if err != nil {
+		return "", fmt.Errorf("error rendering helm chart %s: %w", source.ChartPath, err)
+	}
+
+	manifest := strings.Builder{}
+	for _, crd := range chrt.CRDObjects() {
+		manifest.WriteString("---\n")
+		manifest.Write(crd.File.Data)
+		manifest.WriteString("\n")
+	}
+	fileNames := make([]string, 0, len(rendered))
+	for fileName := range rendered {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+	for _, fileName := range fileNames {
+		content := strings.TrimSpace(rendered[fileName])
+		if content == "" || path.Base(fileName) == helmNotesFile {
+			continue
+		}
+		manifest.WriteString("---\n# Source: " + fileName + "\n")
+		manifest.WriteString(content)
+		manifest.WriteString("\n")
+	}
+	return manifest.String(), nil
+}
+
+// getHelmValues merges the values files in order and the inline values on top of them.
+// Values of later sources take precedence, the chart defaults are coalesced during rendering.
+func getHelmValues(source *v1alpha1.HelmSource) (map[string]any, error) {
+	vals := map[string]any{}
+	for _, valuesFile := range source.ValuesFiles {
+		fileVals, err := chartutil.ReadValuesFile(valuesFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading helm values file %s: %w", valuesFile, err)
+		}
+		vals = chartutil.CoalesceTables(fileVals.AsMap(), vals)
+	}
+
+	if source.Values != nil && len(source.Values.Raw) > 0 {
+		inlineVals := map[string]any{}
+		if err := yaml.Unmarshal(source.Values.Raw, &inlineVals); err != nil {
+			return nil, fmt.Errorf("%w: %w", errInvalidHelmValues, err)
+		}
+		vals = chartutil.CoalesceTables(inlineVals, vals)
+	}
+	return vals, nil
+}
+
+// setDefaultNamespace sets the namespace on all objects without one, like helm does during installation.
+// Cluster-scoped objects are left untouched. The scope of kinds which are not yet known to the cluster
+// is taken from CRDs shipped in the same manifest.
+func setDefaultNamespace(resources *ManifestResources, namespace string, mapper meta.RESTMapper) {
+	crds := getManifestCRDs(resources.Items)
+
+	for _, obj := range resources.Items {
+		if obj.GetNamespace() != "" {
+			continue
+		}
+		gvk := obj.GroupVersionKind()
+		if mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version); err == nil {
+			if mapping.Scope.Name() == meta.RESTScopeNameRoot {
+				continue
+			}
+		} else if crd, found := crds[gvk.GroupKind()]; found {
+			if scope, _, _ := unstructured.NestedString(crd.Object, "spec", "scope"); scope == crdScopeCluster {
+				continue
+			}
+		}
+		obj.SetNamespace(namespace)
+	}
+}
@@ -0,0 +1,63 @@
+package controllers_test
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const helmPodName = "busybox-helm-pod"
+
+var _ = Describe("Sample CR is created with a Helm chart", Ordered, func() {
+	sampleCR := createSampleCR("helm-sample", "")
+	sampleCR.Spec.Helm = &v1alpha1.HelmSource{
+		ChartPath:   "./test/busybox",
+		ValuesFiles: []string{"./test/values/busybox-override.yaml"},
+		Values:      &runtime.RawExtension{Raw: []byte(`{"image":{"tag":"1.36"}}`)},
+	}
+	sampleCRKey := client.ObjectKeyFromObject(sampleCR)
+
+	It("should render the chart into the namespace of the SampleCR", func() {
+		Expect(k8sClient.Create(ctx, sampleCR)).To(Succeed())
+
+		Eventually(getPod(metav1.NamespaceDefault, helmPodName)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeTrue())
+
+		Eventually(getCRStatus(sampleCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Equal(CRStatus{State: v1alpha1.StateReady, InstallConditionStatus: metav1.ConditionTrue, Err: nil}))
+
+		pod := &v1.Pod{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: helmPodName}, pod)).
+			To(Succeed())
+		Expect(pod.Spec.Containers[0].Image).To(Equal("busybox:1.36"))
+
+		service := &v1.Service{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: helmPodName}, service)).
+			To(Succeed())
+		Expect(service.Spec.Ports[0].Port).To(Equal(int32(8080)))
+	})
+
+	It("should delete the rendered resources with the SampleCR", func() {
+		Expect(k8sClient.Delete(ctx, sampleCR)).To(Succeed())
+		Eventually(func() bool {
+			err := k8sClient.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: helmPodName}, &v1.Pod{})
+			return errors.IsNotFound(err) && errors.IsNotFound(k8sClient.Get(ctx, sampleCRKey, &v1alpha1.Sample{}))
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeTrue())
+	})
+})
@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+)
+
+// inventoryKey identifies an inventory entry independent of the served version of its kind,
+// so that switching the apiVersion of an object in the manifest does not prune it.
+type inventoryKey struct {
+	schema.GroupKind
+	client.ObjectKey
+}
+
+func newInventoryEntry(obj *unstructured.Unstructured) v1alpha1.InventoryEntry {
+	gvk := obj.GroupVersionKind()
+	return v1alpha1.InventoryEntry{
+		Group:     gvk.Group,
+		Version:   gvk.Version,
+		Kind:      gvk.Kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+	}
+}
+
+func getInventoryKey(entry v1alpha1.InventoryEntry) inventoryKey {
+	return inventoryKey{
+		GroupKind: schema.GroupKind{Group: entry.Group, Kind: entry.Kind},
+		ObjectKey: client.ObjectKey{Namespace: entry.Namespace, Name: entry.Name},
+	}
+}
+
+// getObjectFromInventoryEntry returns an unstructured object identifying the object of the inventory entry.
+func getObjectFromInventoryEntry(entry v1alpha1.InventoryEntry) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(schema.GroupVersionKind{Group: entry.Group, Version: entry.Version, Kind: entry.Kind})
+	obj.SetNamespace(entry.Namespace)
+	obj.SetName(entry.Name)
+	return obj
+}
+
+// getInventoryFromResources returns the inventory entries of all objects of the manifest, in manifest order.
+func getInventoryFromResources(resources []*unstructured.Unstructured) []v1alpha1.InventoryEntry {
+	inventory := make([]v1alpha1.InventoryEntry, 0, len(resources))
+	for _, obj := range resources {
+		inventory = append(inventory, newInventoryEntry(obj))
+	}
+	return inventory
+}
+
+// mergeInventory returns the entries of current, followed by all entries of previous which are not part of current.
+func mergeInventory(current, previous []v1alpha1.InventoryEntry) []v1alpha1.InventoryEntry {
+	merged := append(make([]v1alpha1.InventoryEntry, 0, len(current)+len(previous)), current...)
+	return append(merged, getStaleInventory(previous, current)...)
+}
+
+// getStaleInventory returns all entries of previous which are not part of current.
+func getStaleInventory(previous, current []v1alpha1.InventoryEntry) []v1alpha1.InventoryEntry {
+	currentKeys := make(map[inventoryKey]struct{}, len(current))
+	for _, entry := range current {
+		currentKeys[getInventoryKey(entry)] = struct{}{}
+	}
+	stale := make([]v1alpha1.InventoryEntry, 0)
+	for _, entry := range previous {
+		if _, found := currentKeys[getInventoryKey(entry)]; !found {
+			stale = append(stale, entry)
+		}
+	}
+	return stale
+}
+
+// pruneResources deletes all objects of the previous inventory which are no longer part of the manifest,
+// unless their DeletionPolicy keeps them.
+// The returned inventory keeps the entries of objects which could not be pruned, so they are retried.
+func (r *SampleReconciler) pruneResources(ctx context.Context, objectInstance *v1alpha1.Sample,
+	previous, current []v1alpha1.InventoryEntry,
+) ([]v1alpha1.InventoryEntry, error) {
+	stale := getStaleInventory(previous, current)
+	if len(stale) == 0 {
+		return current, nil
+	}
+
+	r.Eventf(objectInstance, nil, "Normal", "ResourcesPrune", "Processing", "pruning %d resources", len(stale))
+	for i := len(stale) - 1; i >= 0; i-- {
+		if err := r.deleteResource(ctx, objectInstance, stale[i]); err != nil {
+			return mergeInventory(current, stale[:i+1]), fmt.Errorf("error during pruning of resources: %w", err)
+		}
+	}
+	return current, nil
+}
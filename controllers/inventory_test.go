@@ -0,0 +1,84 @@
+package controllers_test
+
+import (
+	"os"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sample CR prunes resources removed from the manifest", Ordered, func() {
+	sampleCR := createSampleCR("prune-sample", "./test/prune")
+	sampleCRKey := client.ObjectKeyFromObject(sampleCR)
+	keptKey := client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "prune-kept"}
+	removedKey := client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "prune-removed"}
+	retainedKey := client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "prune-retained"}
+
+	It("should record all applied resources in the inventory", func() {
+		Expect(k8sClient.Create(ctx, sampleCR)).To(Succeed())
+
+		Eventually(getInventoryNames(sampleCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(ConsistOf(keptKey.Name, removedKey.Name, retainedKey.Name))
+		Expect(k8sClient.Get(ctx, removedKey, &v1.ConfigMap{})).To(Succeed())
+	})
+
+	It("should prune resources which are no longer part of the manifest", func() {
+		Expect(k8sClient.Get(ctx, sampleCRKey, sampleCR)).To(Succeed())
+		sampleCR.Spec.ExcludePatterns = []string{"pruned.yaml"}
+		Expect(k8sClient.Update(ctx, sampleCR)).To(Succeed())
+
+		Eventually(getInventoryNames(sampleCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(ConsistOf(keptKey.Name))
+		Expect(errors.IsNotFound(k8sClient.Get(ctx, removedKey, &v1.ConfigMap{}))).To(BeTrue())
+		Expect(k8sClient.Get(ctx, keptKey, &v1.ConfigMap{})).To(Succeed())
+
+		retained := &v1.ConfigMap{}
+		Expect(k8sClient.Get(ctx, retainedKey, retained)).To(Succeed())
+		Expect(retained.GetLabels()).NotTo(HaveKey("sample.kyma-project.io/name"))
+	})
+
+	It("should delete the inventory resources even if the manifest is no longer available", func() {
+		Expect(k8sClient.Get(ctx, sampleCRKey, sampleCR)).To(Succeed())
+		sampleCR.Spec.ResourceFilePath = createTempManifestPath("removed-manifest")
+		Expect(k8sClient.Update(ctx, sampleCR)).To(Succeed())
+		Expect(os.RemoveAll(sampleCR.Spec.ResourceFilePath)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, sampleCR)).To(Succeed())
+
+		Eventually(func() bool {
+			return errors.IsNotFound(k8sClient.Get(ctx, keptKey, &v1.ConfigMap{})) &&
+				errors.IsNotFound(k8sClient.Get(ctx, sampleCRKey, &v1alpha1.Sample{}))
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeTrue())
+	})
+
+	AfterAll(func() {
+		configMap := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: retainedKey.Namespace, Name: retainedKey.Name}}
+		Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, configMap))).To(Succeed())
+	})
+})
+
+func getInventoryNames(sampleObjKey client.ObjectKey) func(g Gomega) []string {
+	return func(gomega Gomega) []string {
+		sampleCR := &v1alpha1.Sample{}
+		gomega.Expect(k8sClient.Get(ctx, sampleObjKey, sampleCR)).To(Succeed())
+		names := make([]string, 0, len(sampleCR.Status.Inventory))
+		for _, entry := range sampleCR.Status.Inventory {
+			names = append(names, entry.Name)
+		}
+		return names
+	}
+}
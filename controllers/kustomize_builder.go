@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/kustomize/kyaml/resid"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+)
+
+const (
+	kustomizeOverlayRoot      = "/overlay"
+	kustomizeOverlayResources = "resources.yaml"
+)
+
+// buildKustomization builds the kustomization referenced by the KustomizeSource in-process and returns
+// the resulting multi-document manifest. The NamePrefix, Namespace and Patches of the source are applied
+// through a generated overlay on top of the built kustomization.
+func buildKustomization(source *v1alpha1.KustomizeSource) (string, error) {
+	kustomizer := krusty.MakeKustomizer(krusty.MakeDefaultOptions())
+
+	path, err := filepath.Abs(source.Path)
+	if err != nil {
+		return "", fmt.Errorf("error resolving kustomization path %s: %w", source.Path, err)
+	}
+	resMap, err := kustomizer.Run(filesys.MakeFsOnDisk(), path)
+	if err != nil {
+		return "", fmt.Errorf("error building kustomization %s: %w", source.Path, err)
+	}
+	manifest, err := resMap.AsYaml()
+	if err != nil {
+		return "", fmt.Errorf("error serializing kustomization %s: %w", source.Path, err)
+	}
+
+	if source.NamePrefix == "" && source.Namespace == "" && len(source.Patches) == 0 {
+		return string(manifest), nil
+	}
+
+	overlay, err := yaml.Marshal(getKustomizeOverlay(source))
+	if err != nil {
+		return "", fmt.Errorf("error generating overlay for kustomization %s: %w", source.Path, err)
+	}
+	overlayFs := filesys.MakeFsInMemory()
+	if err := overlayFs.WriteFile(filepath.Join(kustomizeOverlayRoot, kustomizeOverlayResources), manifest); err != nil {
+		return "", fmt.Errorf("error writing overlay resources for kustomization %s: %w", source.Path, err)
+	}
+	if err := overlayFs.WriteFile(filepath.Join(kustomizeOverlayRoot, "kustomization.yaml"), overlay); err != nil {
+		return "", fmt.Errorf("error writing overlay for kustomization %s: %w", source.Path, err)
+	}
+
+	resMap, err = kustomizer.Run(overlayFs, kustomizeOverlayRoot)
+	if err != nil {
+		return "", fmt.Errorf("error building overlay for kustomization %s: %w", source.Path, err)
+	}
+	manifest, err = resMap.AsYaml()
+	if err != nil {
+		return "", fmt.Errorf("error serializing overlay for kustomization %s: %w", source.Path, err)
+	}
+	return string(manifest), nil
+}
+
+func getKustomizeOverlay(source *v1alpha1.KustomizeSource) *types.Kustomization {
+	overlay := &types.Kustomization{
+		TypeMeta: types.TypeMeta{
+			APIVersion: types.KustomizationVersion,
+			Kind:       types.KustomizationKind,
+		},
+		Resources:  []string{kustomizeOverlayResources},
+		NamePrefix: source.NamePrefix,
+		Namespace:  source.Namespace,
+	}
+	for _, patch := range source.Patches {
+		overlayPatch := types.Patch{Patch: patch.Patch}
+		if target := patch.Target; target != nil {
+			overlayPatch.Target = &types.Selector{
+				ResId: resid.ResId{
+					Gvk:       resid.Gvk{Group: target.Group, Version: target.Version, Kind: target.Kind},
+					Name:      target.Name,
+					Namespace: target.Namespace,
+				},
+				LabelSelector:      target.LabelSelector,
+				AnnotationSelector: target.AnnotationSelector,
+			}
+		}
+		overlay.Patches = append(overlay.Patches, overlayPatch)
+	}
+	return overlay
+}
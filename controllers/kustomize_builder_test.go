@@ -0,0 +1,56 @@
+package controllers_test
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sample CR is created with a kustomization", Ordered, func() {
+	sampleCR := createSampleCR("kustomize-sample", "")
+	sampleCR.Spec.Kustomize = &v1alpha1.KustomizeSource{
+		Path:       "./test/kustomize/overlay",
+		NamePrefix: "sample-",
+		Namespace:  metav1.NamespaceDefault,
+		Patches: []v1alpha1.KustomizePatch{{
+			Patch:  `[{"op": "replace", "path": "/data/replicas", "value": "3"}]`,
+			Target: &v1alpha1.KustomizePatchTarget{Kind: "ConfigMap", Name: "kustomize-config"},
+		}},
+	}
+	sampleCRKey := client.ObjectKeyFromObject(sampleCR)
+	configMapKey := client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "sample-kustomize-config"}
+
+	It("should build the kustomization with the overlay of the SampleCR", func() {
+		Expect(k8sClient.Create(ctx, sampleCR)).To(Succeed())
+
+		Eventually(getCRStatus(sampleCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Equal(CRStatus{State: v1alpha1.StateReady, InstallConditionStatus: metav1.ConditionTrue, Err: nil}))
+
+		configMap := &v1.ConfigMap{}
+		Expect(k8sClient.Get(ctx, configMapKey, configMap)).To(Succeed())
+		Expect(configMap.Data).To(HaveKeyWithValue("environment", "overlay"))
+		Expect(configMap.Data).To(HaveKeyWithValue("replicas", "3"))
+		Expect(configMap.Labels).To(HaveKeyWithValue("app.kubernetes.io/part-of", "template-operator"))
+	})
+
+	It("should delete the built resources with the SampleCR", func() {
+		Expect(k8sClient.Delete(ctx, sampleCR)).To(Succeed())
+		Eventually(func() bool {
+			return errors.IsNotFound(k8sClient.Get(ctx, configMapKey, &v1.ConfigMap{})) &&
+				errors.IsNotFound(k8sClient.Get(ctx, sampleCRKey, &v1alpha1.Sample{}))
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeTrue())
+	})
+})
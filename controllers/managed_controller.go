@@ -0,0 +1,277 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	errors2 "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+)
+
+// sampleRefIndex indexes Manageds by the name of the Sample they reference.
+const sampleRefIndex = ".spec.sampleRef.name"
+
+var (
+	errNamespaceMismatch = errors.New("object must be in the namespace of the Managed")
+	// errClusterScoped is returned for cluster-scoped objects, which can not be garbage collected with a Managed
+	errClusterScoped = errors.New("cluster-scoped objects can not be owned by a Managed, reference a Sample instead")
+)
+
+// ManagedReconciler reconciles a Managed object.
+type ManagedReconciler struct {
+	client.Client
+	events.EventRecorder
+
+	Scheme *runtime.Scheme
+	// ResyncPeriod after which a Ready Managed is reconciled, to check the health of its objects
+	ResyncPeriod time.Duration
+}
+
+// +kubebuilder:rbac:groups=operator.kyma-project.io,resources=manageds,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=operator.kyma-project.io,resources=manageds/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=operator.kyma-project.io,resources=manageds/finalizers,verbs=update
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ManagedReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &v1alpha1.Managed{}, sampleRefIndex,
+		func(obj client.Object) []string {
+			managed, ok := obj.(*v1alpha1.Managed)
+			if !ok || managed.Spec.SampleRef == nil {
+				return nil
+			}
+			return []string{managed.Spec.SampleRef.Name}
+		}); err != nil {
+		return fmt.Errorf("error while indexing sample references: %w", err)
+	}
+
+	if err := ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.Managed{}).
+		Watches(&v1alpha1.Sample{}, handler.EnqueueRequestsFromMapFunc(r.getManagedRequests)).
+		Complete(r); err != nil {
+		return fmt.Errorf("error while setting up controller: %w", err)
+	}
+	return nil
+}
+
+// getManagedRequests maps an event of a Sample to a reconciliation of the Manageds referencing it.
+func (r *ManagedReconciler) getManagedRequests(ctx context.Context, obj client.Object) []reconcile.Request {
+	manageds := &v1alpha1.ManagedList{}
+	if err := r.List(ctx, manageds, client.InNamespace(obj.GetNamespace()),
+		client.MatchingFields{sampleRefIndex: obj.GetName()}); err != nil {
+		log.FromContext(ctx).Error(err, "error while listing manageds referencing "+obj.GetName())
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(manageds.Items))
+	for _, managed := range manageds.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&managed)})
+	}
+	return requests
+}
+
+// Reconcile is the entry point from the controller-runtime framework.
+// It performs a reconciliation based on the passed ctrl.Request object.
+func (r *ManagedReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	managed := &v1alpha1.Managed{}
+	if err := r.Get(ctx, req.NamespacedName, managed); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	// the objects of a Managed are garbage collected through their owner references
+	if !managed.GetDeletionTimestamp().IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	status := *managed.Status.DeepCopy()
+	var err error
+	if managed.Spec.SampleRef != nil {
+		err = r.reflectSample(ctx, managed, &status)
+	} else if err = r.removeSampleOwnerReferences(ctx, managed, nil); err == nil {
+		err = r.applyManagedResources(ctx, managed, &status)
+	}
+	if err != nil {
+		r.Eventf(managed, nil, "Warning", "ResourcesInstall", "Processing", "%v", err)
+		status.WithState(v1alpha1.StateError).
+			WithInstalledCondition(metav1.ConditionFalse, getConditionReason(err), err.Error(), managed.GetGeneration())
+		return ctrl.Result{}, errors.Join(err, updateStatusIfChanged(ctx, r.Client, managed, &managed.Status, &status))
+	}
+
+	result := ctrl.Result{RequeueAfter: getResyncPeriod(r.ResyncPeriod)}
+	if status.State == v1alpha1.StateProcessing {
+		result.RequeueAfter = requeueInterval
+	}
+	return result, updateStatusIfChanged(ctx, r.Client, managed, &managed.Status, &status)
+}
+
+// reflectSample makes the Managed a dependent of the referenced Sample and reflects the state of the Sample.
+func (r *ManagedReconciler) reflectSample(ctx context.Context, managed *v1alpha1.Managed,
+	status *v1alpha1.ManagedStatus,
+) error {
+	// the objects applied from spec.resources before the Sample was referenced are no longer managed
+	if err := r.pruneStaleInventory(ctx, status, nil); err != nil {
+		return err
+	}
+
+	sample := &v1alpha1.Sample{}
+	sampleKey := client.ObjectKey{Namespace: managed.GetNamespace(), Name: managed.Spec.SampleRef.Name}
+	if err := r.Get(ctx, sampleKey, sample); err != nil {
+		if errors2.IsNotFound(err) {
+			return errors.Join(withConditionReason(v1alpha1.ConditionReasonSampleNotFound,
+				fmt.Errorf("sample %s not found", sampleKey)), r.removeSampleOwnerReferences(ctx, managed, nil))
+		}
+		return fmt.Errorf("error while getting sample %s: %w", sampleKey, err)
+	}
+
+	// a Sample referenced before must not garbage collect the Managed anymore
+	if err := r.removeSampleOwnerReferences(ctx, managed, sample); err != nil {
+		return err
+	}
+	if !hasOwnerReference(managed, sample) {
+		if err := controllerutil.SetOwnerReference(sample, managed, r.Scheme); err != nil {
+			return fmt.Errorf("error while setting owner reference: %w", err)
+		}
+		if err := r.Update(ctx, managed); err != nil {
+			return fmt.Errorf("error while setting owner reference: %w", err)
+		}
+	}
+	state := sample.Status.State
+	if state == "" {
+		state = v1alpha1.StateProcessing
+	}
+	status.WithState(state)
+	condition := meta.FindStatusCondition(sample.Status.Conditions, v1alpha1.ConditionTypeInstalled)
+	if condition != nil {
+		status.WithInstalledCondition(condition.Status, condition.Reason, condition.Message, managed.GetGeneration())
+	} else {
+		status.WithInstalledCondition(metav1.ConditionUnknown, v1alpha1.ConditionReasonInstalling,
+			fmt.Sprintf("waiting for sample %s", sampleKey), managed.GetGeneration())
+	}
+	return nil
+}
+
+// applyManagedResources applies the inline objects of the Managed, owned by it, and prunes removed ones.
+func (r *ManagedReconciler) applyManagedResources(ctx context.Context, managed *v1alpha1.Managed,
+	status *v1alpha1.ManagedStatus,
+) error {
+	resources, err := getManagedResources(managed, r.RESTMapper())
+	if err != nil {
+		return withConditionReason(v1alpha1.ConditionReasonManifestInvalid, err)
+	}
+	sortResourcesForApply(resources)
+
+	applied := make([]v1alpha1.InventoryEntry, 0, len(resources))
+	for _, obj := range resources {
+		if err := controllerutil.SetControllerReference(managed, obj, r.Scheme); err != nil {
+			return withConditionReason(v1alpha1.ConditionReasonApplyFailed,
+				fmt.Errorf("error while setting owner reference on %s: %w", describeObject(obj), err))
+		}
+		if err := ssa(ctx, r.Client, obj); err != nil {
+			status.WithInventory(mergeInventory(applied, status.Inventory))
+			return withConditionReason(v1alpha1.ConditionReasonApplyFailed,
+				fmt.Errorf("error while applying %s: %w", describeObject(obj), err))
+		}
+		applied = append(applied, newInventoryEntry(obj))
+	}
+
+	if err := r.pruneStaleInventory(ctx, status, applied); err != nil {
+		return err
+	}
+
+	results, err := checkResourcesHealth(ctx, r.Client, resources, nil)
+	if err != nil {
+		return withConditionReason(v1alpha1.ConditionReasonHealthCheckFailed, err)
+	}
+	switch health, message := aggregateHealth(results); health {
+	case v1alpha1.HealthHealthy:
+		status.WithState(v1alpha1.StateReady).WithInstalledCondition(metav1.ConditionTrue,
+			v1alpha1.ConditionReasonReady, "installation is ready and resources can be used", managed.GetGeneration())
+	case v1alpha1.HealthDegraded:
+		status.WithState(v1alpha1.StateWarning).WithInstalledCondition(metav1.ConditionFalse,
+			v1alpha1.ConditionReasonResourcesDegraded, message, managed.GetGeneration())
+	default:
+		status.WithState(v1alpha1.StateProcessing).WithInstalledCondition(metav1.ConditionUnknown,
+			v1alpha1.ConditionReasonWaitingForResources, message, managed.GetGeneration())
+	}
+	return nil
+}
+
+// pruneStaleInventory deletes the objects of the inventory of the Managed which are not applied anymore,
+// and records the applied objects as its inventory. Objects which could not be pruned stay in the inventory.
+func (r *ManagedReconciler) pruneStaleInventory(ctx context.Context, status *v1alpha1.ManagedStatus,
+	applied []v1alpha1.InventoryEntry,
+) error {
+	for _, entry := range getStaleInventory(status.Inventory, applied) {
+		if err := deleteObject(ctx, r.Client, getObjectFromInventoryEntry(entry)); client.IgnoreNotFound(err) != nil {
+			status.WithInventory(mergeInventory(applied, status.Inventory))
+			return withConditionReason(v1alpha1.ConditionReasonPruneFailed,
+				fmt.Errorf("error during pruning of resources: %w", err))
+		}
+	}
+	status.WithInventory(applied)
+	return nil
+}
+
+// removeSampleOwnerReferences removes the owner references to Samples from the Managed, except the one to keep.
+func (r *ManagedReconciler) removeSampleOwnerReferences(ctx context.Context, managed *v1alpha1.Managed,
+	keep *v1alpha1.Sample,
+) error {
+	refs := slices.DeleteFunc(slices.Clone(managed.GetOwnerReferences()), func(ref metav1.OwnerReference) bool {
+		isSample := ref.Kind == string(v1alpha1.SampleKind) &&
+			strings.HasPrefix(ref.APIVersion, v1alpha1.GroupVersion.Group+"/")
+		return isSample && (keep == nil || ref.UID != keep.GetUID())
+	})
+	if len(refs) == len(managed.GetOwnerReferences()) {
+		return nil
+	}
+	managed.SetOwnerReferences(refs)
+	if err := r.Update(ctx, managed); err != nil {
+		return fmt.Errorf("error while removing owner reference: %w", err)
+	}
+	return nil
+}
+
+// getManagedResources parses the inline objects of the Managed, defaulting the namespace of namespaced objects
+// to the one of the Managed. Cluster-scoped objects are rejected, as they can not be owned by the Managed.
+func getManagedResources(managed *v1alpha1.Managed, mapper meta.RESTMapper) ([]*unstructured.Unstructured, error) {
+	resources := &ManifestResources{Items: make([]*unstructured.Unstructured, 0, len(managed.Spec.Resources))}
+	for i, raw := range managed.Spec.Resources {
+		obj, err := parseManifestObject(raw.Raw)
+		if err != nil {
+			return nil, fmt.Errorf("resource %d of managed %s: %w", i, managed.GetName(), err)
+		}
+		resources.Items = append(resources.Items, obj)
+	}
+	setDefaultNamespace(resources, managed.GetNamespace(), mapper)
+	for i, obj := range resources.Items {
+		if obj.GetNamespace() == "" {
+			return nil, fmt.Errorf("resource %d of managed %s: %w", i, managed.GetName(), errClusterScoped)
+		}
+		if obj.GetNamespace() != managed.GetNamespace() {
+			return nil, fmt.Errorf("resource %d of managed %s: %w", i, managed.GetName(), errNamespaceMismatch)
+		}
+	}
+	return resources.Items, nil
+}
+
+func hasOwnerReference(obj, owner client.Object) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID == owner.GetUID() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,171 @@
+package controllers_test
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Managed CR is created with inline resources", Ordered, func() {
+	managedCR := &v1alpha1.Managed{
+		ObjectMeta: metav1.ObjectMeta{Name: "inline-managed", Namespace: metav1.NamespaceDefault},
+		Spec: v1alpha1.ManagedSpec{Resources: []runtime.RawExtension{{Raw: []byte(
+			`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"inline-managed-config"},"data":{"key":"value"}}`,
+		)}}},
+	}
+	managedCRKey := client.ObjectKeyFromObject(managedCR)
+	configKey := client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "inline-managed-config"}
+
+	It("should apply the resources owned by the Managed CR", func() {
+		Expect(k8sClient.Create(ctx, managedCR)).To(Succeed())
+
+		Eventually(getManagedState(managedCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Equal(v1alpha1.StateReady))
+		Expect(k8sClient.Get(ctx, managedCRKey, managedCR)).To(Succeed())
+		configMap := &v1.ConfigMap{}
+		Expect(k8sClient.Get(ctx, configKey, configMap)).To(Succeed())
+		Expect(configMap.GetOwnerReferences()).To(ContainElement(And(
+			HaveField("Kind", string(v1alpha1.ManagedKind)),
+			HaveField("UID", managedCR.GetUID()),
+		)))
+		Expect(managedCR.Status.Inventory).To(ConsistOf(HaveField("Name", "inline-managed-config")))
+	})
+
+	It("should prune the resources removed from the Managed CR", func() {
+		Expect(k8sClient.Get(ctx, managedCRKey, managedCR)).To(Succeed())
+		managedCR.Spec.Resources = []runtime.RawExtension{{Raw: []byte(
+			`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"inline-managed-other"}}`,
+		)}}
+		Expect(k8sClient.Update(ctx, managedCR)).To(Succeed())
+
+		Eventually(func() bool {
+			return errors.IsNotFound(k8sClient.Get(ctx, configKey, &v1.ConfigMap{}))
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeTrue())
+	})
+
+	It("should reject cluster-scoped resources, which the Managed CR can not own", func() {
+		Expect(k8sClient.Get(ctx, managedCRKey, managedCR)).To(Succeed())
+		managedCR.Spec.Resources = append(managedCR.Spec.Resources, runtime.RawExtension{Raw: []byte(
+			`{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"ClusterRole","metadata":{"name":"inline-managed-role"}}`,
+		)})
+		Expect(k8sClient.Update(ctx, managedCR)).To(Succeed())
+
+		Eventually(func(g Gomega) *metav1.Condition {
+			g.Expect(k8sClient.Get(ctx, managedCRKey, managedCR)).To(Succeed())
+			return meta.FindStatusCondition(managedCR.Status.Conditions, v1alpha1.ConditionTypeInstalled)
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(And(
+				HaveField("Reason", v1alpha1.ConditionReasonManifestInvalid),
+				HaveField("Message", ContainSubstring("cluster-scoped")),
+			))
+	})
+
+	It("should prune the resources once the Managed CR references a Sample CR", func() {
+		Expect(k8sClient.Get(ctx, managedCRKey, managedCR)).To(Succeed())
+		managedCR.Spec.Resources = nil
+		managedCR.Spec.SampleRef = &v1alpha1.SampleReference{Name: "inline-managed-sample"}
+		Expect(k8sClient.Update(ctx, managedCR)).To(Succeed())
+
+		Eventually(func(g Gomega) []v1alpha1.InventoryEntry {
+			g.Expect(k8sClient.Get(ctx, managedCRKey, managedCR)).To(Succeed())
+			return managedCR.Status.Inventory
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeEmpty())
+		Expect(errors.IsNotFound(k8sClient.Get(ctx,
+			client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "inline-managed-other"}, &v1.ConfigMap{}))).
+			To(BeTrue())
+		Expect(k8sClient.Delete(ctx, managedCR)).To(Succeed())
+	})
+})
+
+var _ = Describe("Managed CR is created with a reference to a Sample CR", Ordered, func() {
+	sampleCR := createSampleCR("managed-sample", "")
+	managedCR := &v1alpha1.Managed{
+		ObjectMeta: metav1.ObjectMeta{Name: "sample-managed", Namespace: metav1.NamespaceDefault},
+		Spec:       v1alpha1.ManagedSpec{SampleRef: &v1alpha1.SampleReference{Name: sampleCR.GetName()}},
+	}
+	managedCRKey := client.ObjectKeyFromObject(managedCR)
+
+	BeforeAll(func() {
+		sampleCR.Spec.ResourceFilePath = createConfigMapManifest("managed-sample-config")
+	})
+
+	It("should report the missing Sample CR", func() {
+		Expect(k8sClient.Create(ctx, managedCR)).To(Succeed())
+
+		Eventually(getManagedState(managedCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Equal(v1alpha1.StateError))
+		Expect(k8sClient.Get(ctx, managedCRKey, managedCR)).To(Succeed())
+		Expect(meta.FindStatusCondition(managedCR.Status.Conditions, v1alpha1.ConditionTypeInstalled)).
+			To(HaveField("Reason", v1alpha1.ConditionReasonSampleNotFound))
+	})
+
+	It("should reflect the state of the Sample CR and become its dependent", func() {
+		Expect(k8sClient.Create(ctx, sampleCR)).To(Succeed())
+
+		Eventually(getManagedState(managedCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Equal(v1alpha1.StateReady))
+		Expect(k8sClient.Get(ctx, managedCRKey, managedCR)).To(Succeed())
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(sampleCR), sampleCR)).To(Succeed())
+		Expect(managedCR.GetOwnerReferences()).To(ContainElement(HaveField("UID", sampleCR.GetUID())))
+	})
+
+	It("should no longer depend on the Sample CR once the reference is removed", func() {
+		Expect(k8sClient.Get(ctx, managedCRKey, managedCR)).To(Succeed())
+		managedCR.Spec.SampleRef = nil
+		managedCR.Spec.Resources = []runtime.RawExtension{{Raw: []byte(
+			`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"sample-managed-config"}}`,
+		)}}
+		Expect(k8sClient.Update(ctx, managedCR)).To(Succeed())
+
+		Eventually(func(g Gomega) []metav1.OwnerReference {
+			g.Expect(k8sClient.Get(ctx, managedCRKey, managedCR)).To(Succeed())
+			return managedCR.GetOwnerReferences()
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			ShouldNot(ContainElement(HaveField("UID", sampleCR.GetUID())))
+	})
+
+	It("should delete the resources with the SampleCR", func() {
+		Expect(k8sClient.Delete(ctx, managedCR)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, sampleCR)).To(Succeed())
+		Eventually(func() bool {
+			return errors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(sampleCR), &v1alpha1.Sample{}))
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeTrue())
+	})
+})
+
+func getManagedState(managedObjKey client.ObjectKey) func(g Gomega) v1alpha1.State {
+	return func(gomega Gomega) v1alpha1.State {
+		managedCR := &v1alpha1.Managed{}
+		gomega.Expect(k8sClient.Get(ctx, managedObjKey, managedCR)).To(Succeed())
+		return managedCR.Status.State
+	}
+}
@@ -0,0 +1,198 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+)
+
+var (
+	errNoManifestFiles = errors.New("no .yaml or .yml manifest files found")
+	errInvalidPattern  = errors.New("invalid file pattern")
+	errManifestInvalid = errors.New("manifest contains invalid documents")
+)
+
+//nolint:gochecknoglobals // immutable set of supported manifest file extensions
+var manifestFileExtensions = sets.New(".yaml", ".yml")
+
+// loadManifestResources returns the resources of the Sample from its configured manifest source, traced in a span.
+func (r *SampleReconciler) loadManifestResources(ctx context.Context,
+	objectInstance *v1alpha1.Sample,
+) (*ManifestResources, error) {
+	ctx, span := startSpan(ctx, "loadManifestResources", append(sampleAttributes(objectInstance),
+		attributeManifestSource.String(getSourceKind(&objectInstance.Spec)))...)
+	resources, err := r.loadManifestFromSource(objectInstance, log.FromContext(ctx))
+	if err == nil {
+		span.SetAttributes(attributeManifestObjects.Int(len(resources.Items)))
+	}
+	endSpan(span, err)
+	return resources, err
+}
+
+// loadManifestFromSource returns the resources of the Sample from its configured manifest source.
+// A Helm chart takes precedence over a kustomization, which takes precedence over the manifest files
+// at ResourceFilePath.
+func (r *SampleReconciler) loadManifestFromSource(objectInstance *v1alpha1.Sample,
+	logger logr.Logger,
+) (*ManifestResources, error) {
+	spec := objectInstance.Spec
+	if spec.Helm != nil {
+		manifest, err := renderHelmChart(spec.Helm, objectInstance.GetName(), objectInstance.GetNamespace())
+		if err != nil {
+			return nil, err
+		}
+		resources := parseManifestStringToObjects("helm chart "+spec.Helm.ChartPath, manifest)
+		setDefaultNamespace(resources, objectInstance.GetNamespace(), r.RESTMapper())
+		return resources, nil
+	}
+
+	if spec.Kustomize != nil {
+		manifest, err := buildKustomization(spec.Kustomize)
+		if err != nil {
+			return nil, err
+		}
+		return parseManifestStringToObjects("kustomization "+spec.Kustomize.Path, manifest), nil
+	}
+
+	return getResourcesFromLocalPath(spec.ResourceFilePath, spec.IncludePatterns, spec.ExcludePatterns, logger)
+}
+
+// checkManifestDocuments reports the invalid documents of the manifest in the ManifestInvalid condition
+// and an event. With Strict ManifestParsing, a manifest with invalid documents is refused entirely.
+func (r *SampleReconciler) checkManifestDocuments(objectInstance *v1alpha1.Sample, status *v1alpha1.SampleStatus,
+	resources *ManifestResources,
+) error {
+	if len(resources.Invalid) == 0 {
+		status.WithManifestInvalidCondition(false, "all documents are valid objects", objectInstance.GetGeneration())
+		return nil
+	}
+
+	messages := make([]string, 0, len(resources.Invalid))
+	for _, err := range resources.Invalid {
+		messages = append(messages, err.Error())
+	}
+	message := joinMessages(messages)
+	status.WithManifestInvalidCondition(true, message, objectInstance.GetGeneration())
+	r.Eventf(objectInstance, nil, "Warning", "ManifestInvalid", "Processing", "%s", message)
+
+	if objectInstance.Spec.ManifestParsing == v1alpha1.ManifestParsingLenient {
+		return nil
+	}
+	return fmt.Errorf("%w: %w", errManifestInvalid, errors.Join(resources.Invalid...))
+}
+
+// getResourcesFromLocalPath returns resources from the dirPath in unstructured format.
+// All .yaml and .yml files below dirPath are loaded recursively, in lexical order of their relative path,
+// and filtered by the include and exclude glob patterns. If dirPath points to a file, only that file is loaded.
+func getResourcesFromLocalPath(dirPath string, includes, excludes []string,
+	logger logr.Logger,
+) (*ManifestResources, error) {
+	if err := validatePatterns(includes, excludes); err != nil {
+		return nil, err
+	}
+
+	files, err := collectManifestFiles(dirPath, includes, excludes)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w at file path %s", errNoManifestFiles, dirPath)
+	}
+
+	resources := &ManifestResources{}
+	for _, file := range files {
+		logger.V(debugLogLevel).Info("loading manifest file " + file)
+		fileBytes, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("yaml file %s could not be read: %w", file, err)
+		}
+		fileResources := parseManifestStringToObjects("file "+file, string(fileBytes))
+		resources.Items = append(resources.Items, fileResources.Items...)
+		resources.Invalid = append(resources.Invalid, fileResources.Invalid...)
+	}
+	return resources, nil
+}
+
+// collectManifestFiles walks dirPath and returns the paths of all manifest files
+// matching the include and exclude patterns, sorted by their path relative to dirPath.
+func collectManifestFiles(dirPath string, includes, excludes []string) ([]string, error) {
+	info, err := os.Stat(dirPath)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading file path %s: %w", dirPath, err)
+	}
+	if !info.IsDir() {
+		if !manifestFileExtensions.Has(filepath.Ext(dirPath)) {
+			return nil, fmt.Errorf("%w: %s is not a .yaml or .yml file", errNoManifestFiles, dirPath)
+		}
+		return []string{dirPath}, nil
+	}
+
+	relPaths := make([]string, 0)
+	err = filepath.WalkDir(dirPath, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("error while walkdir %s: %w", dirPath, err)
+		}
+		if entry.IsDir() || !manifestFileExtensions.Has(filepath.Ext(entry.Name())) {
+			return nil
+		}
+		relPath, err := filepath.Rel(dirPath, filePath)
+		if err != nil {
+			return fmt.Errorf("error resolving relative path of %s: %w", filePath, err)
+		}
+		relPath = filepath.ToSlash(relPath)
+		if (len(includes) > 0 && !matchesAnyPattern(relPath, includes)) || matchesAnyPattern(relPath, excludes) {
+			return nil
+		}
+		relPaths = append(relPaths, relPath)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(relPaths)
+	files := make([]string, 0, len(relPaths))
+	for _, relPath := range relPaths {
+		files = append(files, filepath.Join(dirPath, filepath.FromSlash(relPath)))
+	}
+	return files, nil
+}
+
+// matchesAnyPattern reports whether the slash separated relPath matches one of the glob patterns.
+// Patterns containing a "/" are matched against the full relative path, all others against the file name.
+func matchesAnyPattern(relPath string, patterns []string) bool {
+	for _, pattern := range patterns {
+		name := relPath
+		if !strings.Contains(pattern, "/") {
+			name = path.Base(relPath)
+		}
+		// patterns are validated upfront, so the error can be ignored here
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func validatePatterns(patternLists ...[]string) error {
+	for _, patterns := range patternLists {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("%w %q: %w", errInvalidPattern, pattern, err)
+			}
+		}
+	}
+	return nil
+}
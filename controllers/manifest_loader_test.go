@@ -0,0 +1,67 @@
+package controllers_test
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const multiFileNs = "multi-file"
+
+var _ = Describe("Sample CR is created with a nested multi-file resource path", Ordered, func() {
+	sampleCR := createSampleCR("multi-file-sample", "./test/multi-file")
+	sampleCR.Spec.ExcludePatterns = []string{"skipped.yaml"}
+	sampleCRKey := client.ObjectKeyFromObject(sampleCR)
+
+	It("should install resources of all included files", func() {
+		Expect(k8sClient.Create(ctx, sampleCR)).To(Succeed())
+
+		Eventually(getCRStatus(sampleCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Equal(CRStatus{State: v1alpha1.StateReady, InstallConditionStatus: metav1.ConditionTrue, Err: nil}))
+
+		for _, name := range []string{"app-config", "app-settings", "nested-config"} {
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: multiFileNs, Name: name},
+				&v1.ConfigMap{})).To(Succeed())
+		}
+	})
+
+	It("should not install resources of excluded files", func() {
+		err := k8sClient.Get(ctx, client.ObjectKey{Namespace: multiFileNs, Name: "skipped-config"}, &v1.ConfigMap{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should delete the SampleCR", func() {
+		Expect(k8sClient.Delete(ctx, sampleCR)).To(Succeed())
+		Eventually(func() bool {
+			return errors.IsNotFound(k8sClient.Get(ctx, sampleCRKey, &v1alpha1.Sample{}))
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeTrue())
+	})
+})
+
+var _ = Describe("Sample CR is created with a resource path without manifest files", Ordered, func() {
+	sampleCR := createSampleCR("empty-sample", "./test/empty")
+	sampleCRKey := client.ObjectKeyFromObject(sampleCR)
+
+	It("should create SampleCR in Error state", func() {
+		Expect(k8sClient.Create(ctx, sampleCR)).To(Succeed())
+		Eventually(getCRStatus(sampleCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Equal(CRStatus{State: v1alpha1.StateError, InstallConditionStatus: metav1.ConditionFalse, Err: nil}))
+
+		Expect(k8sClient.Delete(ctx, sampleCR)).To(Succeed())
+	})
+})
@@ -0,0 +1,71 @@
+package controllers_test
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sample CR is created with a manifest containing invalid documents", Ordered, func() {
+	sampleCR := createSampleCR("invalid-document-sample", "./test/invalid-document")
+	sampleCRKey := client.ObjectKeyFromObject(sampleCR)
+	configKey := client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "invalid-document-config"}
+
+	It("should refuse to apply the manifest and report the invalid document", func() {
+		Expect(k8sClient.Create(ctx, sampleCR)).To(Succeed())
+
+		Eventually(getCRStatus(sampleCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Equal(CRStatus{State: v1alpha1.StateError, InstallConditionStatus: metav1.ConditionFalse, Err: nil}))
+		Expect(getManifestInvalidCondition(sampleCRKey)(Default)).To(And(
+			HaveField("Status", metav1.ConditionTrue),
+			HaveField("Message", ContainSubstring("document 2 at line 9")),
+			HaveField("Message", ContainSubstring("object has no kind")),
+		))
+		Expect(errors.IsNotFound(k8sClient.Get(ctx, configKey, &v1.ConfigMap{}))).To(BeTrue())
+	})
+
+	It("should apply the valid objects with Lenient ManifestParsing", func() {
+		Expect(k8sClient.Get(ctx, sampleCRKey, sampleCR)).To(Succeed())
+		sampleCR.Spec.ManifestParsing = v1alpha1.ManifestParsingLenient
+		Expect(k8sClient.Update(ctx, sampleCR)).To(Succeed())
+
+		Eventually(getCRStatus(sampleCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Equal(CRStatus{State: v1alpha1.StateReady, InstallConditionStatus: metav1.ConditionTrue, Err: nil}))
+		Expect(getManifestInvalidCondition(sampleCRKey)(Default)).To(HaveField("Status", metav1.ConditionTrue))
+		Expect(k8sClient.Get(ctx, configKey, &v1.ConfigMap{})).To(Succeed())
+	})
+
+	It("should delete the resources with the SampleCR", func() {
+		Expect(k8sClient.Delete(ctx, sampleCR)).To(Succeed())
+		Eventually(func() bool {
+			return errors.IsNotFound(k8sClient.Get(ctx, configKey, &v1.ConfigMap{})) &&
+				errors.IsNotFound(k8sClient.Get(ctx, sampleCRKey, &v1alpha1.Sample{}))
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeTrue())
+	})
+})
+
+func getManifestInvalidCondition(sampleObjKey client.ObjectKey) func(g Gomega) metav1.Condition {
+	return func(gomega Gomega) metav1.Condition {
+		sampleCR := &v1alpha1.Sample{}
+		gomega.Expect(k8sClient.Get(ctx, sampleObjKey, sampleCR)).To(Succeed())
+		condition := meta.FindStatusCondition(sampleCR.Status.Conditions, v1alpha1.ConditionTypeManifestInvalid)
+		gomega.Expect(condition).NotTo(BeNil())
+		return *condition
+	}
+}
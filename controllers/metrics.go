@@ -0,0 +1,165 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	errors2 "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+)
+
+const (
+	metricsNamespace = "template_operator"
+
+	// applyDurationBuckets range from 50ms to about 25s
+	applyDurationBucketStart  = 0.05
+	applyDurationBucketFactor = 2
+	applyDurationBucketCount  = 10
+	// appliedObjectsBuckets range from 1 to 512 objects
+	appliedObjectsBucketStart  = 1
+	appliedObjectsBucketFactor = 2
+	appliedObjectsBucketCount  = 10
+
+	// collectTimeout limits the time listing the Samples from the cache takes when the metrics are scraped
+	collectTimeout = 5 * time.Second
+)
+
+//nolint:gochecknoglobals // the metrics are registered once on the registry of controller-runtime
+var (
+	stateTransitionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "sample_state_transitions_total",
+		Help:      "Number of transitions of Samples from one State to another",
+	}, []string{"from", "to"})
+	applyDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "sample_apply_duration_seconds",
+		Help:      "Duration of applying the manifest of a Sample, by the kind of its manifest source",
+		Buckets: prometheus.ExponentialBuckets(applyDurationBucketStart, applyDurationBucketFactor,
+			applyDurationBucketCount),
+	}, []string{"source"})
+	appliedObjects = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "sample_applied_objects",
+		Help:      "Number of objects applied from the manifest of a Sample, by the kind of its manifest source",
+		Buckets: prometheus.ExponentialBuckets(appliedObjectsBucketStart, appliedObjectsBucketFactor,
+			appliedObjectsBucketCount),
+	}, []string{"source"})
+	applyErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "apply_errors_total",
+		Help:      "Number of errors applying objects of the manifests, by their kind and the reason of the error",
+	}, []string{"group", "version", "kind", "reason"})
+	deleteErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "delete_errors_total",
+		Help:      "Number of errors deleting or pruning objects, by their kind and the reason of the error",
+	}, []string{"group", "version", "kind", "reason"})
+	driftedObjects = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "drifted_objects",
+		Help: "Number of objects of a Sample which drifted from the manifest at its last reconciliation, " +
+			"by their kind and the DriftPolicy",
+	}, []string{"namespace", "name", "group", "version", "kind", "drift_policy"})
+
+	samplesDesc = prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "", "samples"),
+		"Number of Samples per State", []string{"state"}, nil)
+)
+
+func init() { //nolint:gochecknoinits // used to register the metrics on startup
+	metrics.Registry.MustRegister(stateTransitionsTotal, applyDurationSeconds, appliedObjects,
+		applyErrorsTotal, deleteErrorsTotal, driftedObjects)
+}
+
+// registerSampleCollector registers the collector of the number of Samples per State, which are listed
+// with the reader when the metrics are scraped. A collector registered before is kept.
+func registerSampleCollector(reader client.Reader) error {
+	err := metrics.Registry.Register(&sampleCollector{reader: reader})
+	if alreadyRegistered := (prometheus.AlreadyRegisteredError{}); errors.As(err, &alreadyRegistered) {
+		return nil
+	}
+	return err
+}
+
+// sampleCollector collects the number of Samples per State.
+type sampleCollector struct {
+	reader client.Reader
+}
+
+func (c *sampleCollector) Describe(descs chan<- *prometheus.Desc) {
+	descs <- samplesDesc
+}
+
+func (c *sampleCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+	samples := &v1alpha1.SampleList{}
+	if err := c.reader.List(ctx, samples); err != nil {
+		log.FromContext(ctx).Error(err, "error while listing Samples for metrics")
+		ch <- prometheus.NewInvalidMetric(samplesDesc, err)
+		return
+	}
+
+	counts := map[v1alpha1.State]int{
+		v1alpha1.StateProcessing: 0, v1alpha1.StateReady: 0, v1alpha1.StateWarning: 0,
+		v1alpha1.StateError: 0, v1alpha1.StateDeleting: 0,
+	}
+	for _, sample := range samples.Items {
+		// Samples which were not reconciled yet have no State
+		if _, known := counts[sample.Status.State]; known {
+			counts[sample.Status.State]++
+		}
+	}
+	for state, count := range counts {
+		ch <- prometheus.MustNewConstMetric(samplesDesc, prometheus.GaugeValue, float64(count), string(state))
+	}
+}
+
+// recordStateTransition counts the transition of a Sample from one State to another.
+func recordStateTransition(from, to v1alpha1.State) {
+	if from != to {
+		stateTransitionsTotal.WithLabelValues(string(from), string(to)).Inc()
+	}
+}
+
+// recordApply observes the duration of applying the manifest of the Sample and the number of applied objects.
+func recordApply(objectInstance *v1alpha1.Sample, duration time.Duration, applied int) {
+	source := getSourceKind(&objectInstance.Spec)
+	applyDurationSeconds.WithLabelValues(source).Observe(duration.Seconds())
+	appliedObjects.WithLabelValues(source).Observe(float64(applied))
+}
+
+// recordObjectError counts the error of a call for the object, by its kind and the reason of the error.
+func recordObjectError(counter *prometheus.CounterVec, obj *unstructured.Unstructured, err error) {
+	reason := errors2.ReasonForError(err)
+	if reason == "" {
+		reason = "Unknown"
+	}
+	gvk := obj.GroupVersionKind()
+	counter.WithLabelValues(gvk.Group, gvk.Version, gvk.Kind, string(reason)).Inc()
+}
+
+// recordDrifts replaces the number of drifted objects of the Sample, by their kind and the DriftPolicy handling them,
+// so that a drift left in place by the Report DriftPolicy is not counted again with every reconciliation.
+func recordDrifts(objectInstance *v1alpha1.Sample, drifts []driftResult, driftPolicy v1alpha1.DriftPolicy) {
+	forgetDrifts(objectInstance)
+	for _, drift := range drifts {
+		gvk := drift.obj.GroupVersionKind()
+		driftedObjects.WithLabelValues(objectInstance.GetNamespace(), objectInstance.GetName(),
+			gvk.Group, gvk.Version, gvk.Kind, string(driftPolicy)).Inc()
+	}
+}
+
+// forgetDrifts removes the number of drifted objects of the Sample.
+func forgetDrifts(objectInstance *v1alpha1.Sample) {
+	driftedObjects.DeletePartialMatch(prometheus.Labels{
+		"namespace": objectInstance.GetNamespace(), "name": objectInstance.GetName(),
+	})
+}
@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+)
+
+// maxPlannedFields limits the number of changed fields listed for a single object of the plan.
+const maxPlannedFields = 10
+
+// isDryRun reports whether the Sample is reconciled in dry-run mode, either by its spec or controller-wide.
+func (r *SampleReconciler) isDryRun(objectInstance *v1alpha1.Sample) bool {
+	return r.DryRun || objectInstance.Spec.DryRun
+}
+
+// recordPlan records the plan of changes applying the resources would cause in the status.
+func (r *SampleReconciler) recordPlan(ctx context.Context, objectInstance *v1alpha1.Sample,
+	status *v1alpha1.SampleStatus, resources []*unstructured.Unstructured,
+) error {
+	plan, err := r.planResources(ctx, objectInstance, resources)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "error during planning of resources")
+		return err
+	}
+	r.Eventf(objectInstance, nil, "Normal", "ResourcesPlan", "Processing", "dry-run planned %s", plan.Summary)
+	status.WithPlan(plan)
+	return nil
+}
+
+// recordDeletionPlan records the plan of the deletion of the Sample in the status, without deleting anything.
+// The finalizer is kept, so the resources are deleted once dry-run is disabled.
+func (r *SampleReconciler) recordDeletionPlan(ctx context.Context, objectInstance *v1alpha1.Sample,
+	status *v1alpha1.SampleStatus, inventory []v1alpha1.InventoryEntry,
+) error {
+	plan, err := r.planDeletion(ctx, objectInstance, inventory)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "error during planning of the deletion")
+		return err
+	}
+	r.Eventf(objectInstance, nil, "Normal", "ResourcesPlan", "Deleting", "dry-run planned %s", plan.Summary)
+	status.WithPlan(plan).
+		WithDeletingCondition(v1alpha1.ConditionReasonDryRun,
+			"dry-run planned the deletion without deleting the resources", objectInstance.GetGeneration())
+	return r.setStatusIfChanged(ctx, objectInstance, status)
+}
+
+// planDeletion determines the objects of the inventory which deleting the Sample would delete or retain,
+// in the order they would be handled. Objects which no longer exist are left out.
+func (r *SampleReconciler) planDeletion(ctx context.Context, objectInstance *v1alpha1.Sample,
+	inventory []v1alpha1.InventoryEntry,
+) (*v1alpha1.Plan, error) {
+	plan := &v1alpha1.Plan{Changes: make([]v1alpha1.PlannedChange, 0)}
+	var deleted, retained, orphaned int
+	for i := len(inventory) - 1; i >= 0; i-- {
+		obj := getObjectFromInventoryEntry(inventory[i])
+		if err := r.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			if client.IgnoreNotFound(err) != nil && !meta.IsNoMatchError(err) {
+				return nil, fmt.Errorf("error while planning %s: %w", describeObject(obj), err)
+			}
+			continue
+		}
+		policy, err := getDeletionPolicy(obj, objectInstance.Spec.DeletionPolicy)
+		if err != nil {
+			return nil, err
+		}
+		switch policy {
+		case v1alpha1.DeletionPolicyOrphan:
+			orphaned++
+		case v1alpha1.DeletionPolicyRetain:
+			plan.Changes = append(plan.Changes, v1alpha1.PlannedChange{
+				Action: v1alpha1.PlanActionRetain, InventoryEntry: inventory[i],
+			})
+			retained++
+		default:
+			plan.Changes = append(plan.Changes, v1alpha1.PlannedChange{
+				Action: v1alpha1.PlanActionDelete, InventoryEntry: inventory[i],
+			})
+			deleted++
+		}
+	}
+	plan.Summary = fmt.Sprintf("%d to delete, %d to retain, %d to orphan", deleted, retained, orphaned)
+	return plan, nil
+}
+
+// planResources determines the changes applying the resources would cause, without modifying the cluster.
+// Objects which do not exist are planned to be created, the changes of existing objects are determined
+// with a server-side dry-run and objects of the previous inventory, which are not part of the resources,
+// are planned to be pruned.
+func (r *SampleReconciler) planResources(ctx context.Context, objectInstance *v1alpha1.Sample,
+	resources []*unstructured.Unstructured,
+) (*v1alpha1.Plan, error) {
+	plan := &v1alpha1.Plan{Changes: make([]v1alpha1.PlannedChange, 0)}
+	var created, changed, unchanged int
+	for _, obj := range resources {
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(obj.GroupVersionKind())
+		if err := r.Get(ctx, client.ObjectKeyFromObject(obj), live); err != nil {
+			// kinds of CRDs which are not yet installed are not known to the cluster
+			if client.IgnoreNotFound(err) != nil && !meta.IsNoMatchError(err) {
+				return nil, fmt.Errorf("error while planning %s: %w", describeObject(obj), err)
+			}
+			plan.Changes = append(plan.Changes, v1alpha1.PlannedChange{
+				Action:         v1alpha1.PlanActionCreate,
+				InventoryEntry: newInventoryEntry(obj),
+			})
+			created++
+			continue
+		}
+
+		applied, err := r.dryRunApply(ctx, obj)
+		if err != nil {
+			return nil, fmt.Errorf("error while planning %s: %w", describeObject(obj), err)
+		}
+		fields := make([]string, 0)
+		for _, field := range getDriftedFields(obj, applied, live) {
+			fields = append(fields, field.String())
+		}
+		if len(fields) == 0 {
+			unchanged++
+			continue
+		}
+		if len(fields) > maxPlannedFields {
+			fields = append(fields[:maxPlannedFields:maxPlannedFields],
+				fmt.Sprintf("and %d more", len(fields)-maxPlannedFields))
+		}
+		plan.Changes = append(plan.Changes, v1alpha1.PlannedChange{
+			Action:         v1alpha1.PlanActionChange,
+			InventoryEntry: newInventoryEntry(obj),
+			Fields:         fields,
+		})
+		changed++
+	}
+
+	stale := getStaleInventory(objectInstance.Status.Inventory, getInventoryFromResources(resources))
+	for _, entry := range stale {
+		plan.Changes = append(plan.Changes, v1alpha1.PlannedChange{
+			Action:         v1alpha1.PlanActionPrune,
+			InventoryEntry: entry,
+		})
+	}
+	plan.Summary = fmt.Sprintf("%d to create, %d to change, %d to prune, %d unchanged",
+		created, changed, len(stale), unchanged)
+	return plan, nil
+}
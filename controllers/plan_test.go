@@ -0,0 +1,122 @@
+package controllers_test
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sample CR is created in dry-run mode", Ordered, func() {
+	sampleCR := createSampleCR("plan-sample", "")
+	sampleCR.Spec.DryRun = true
+	sampleCRKey := client.ObjectKeyFromObject(sampleCR)
+	configKey := client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "plan-config"}
+
+	BeforeAll(func() {
+		sampleCR.Spec.ResourceFilePath = createConfigMapManifest(configKey.Name)
+	})
+
+	It("should plan to create the resources without creating them", func() {
+		Expect(k8sClient.Create(ctx, sampleCR)).To(Succeed())
+
+		Eventually(getPlan(sampleCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(HaveField("Summary", "1 to create, 0 to change, 0 to prune, 0 unchanged"))
+		Expect(getPlan(sampleCRKey)(Default).Changes).To(ConsistOf(
+			HaveField("Action", v1alpha1.PlanActionCreate),
+		))
+		Expect(getCRStatus(sampleCRKey)(Default)).To(Equal(CRStatus{
+			State: v1alpha1.StateReady, InstallConditionStatus: metav1.ConditionUnknown, Err: nil,
+		}))
+		Expect(errors.IsNotFound(k8sClient.Get(ctx, configKey, &v1.ConfigMap{}))).To(BeTrue())
+	})
+
+	It("should plan to change the fields of existing resources", func() {
+		Expect(k8sClient.Create(ctx, &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: configKey.Namespace, Name: configKey.Name},
+			Data:       map[string]string{"key": "other"},
+		})).To(Succeed())
+		// trigger a reconciliation of the SampleCR
+		Expect(k8sClient.Get(ctx, sampleCRKey, sampleCR)).To(Succeed())
+		sampleCR.SetAnnotations(map[string]string{"plan": "changed"})
+		Expect(k8sClient.Update(ctx, sampleCR)).To(Succeed())
+
+		Eventually(getPlan(sampleCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(HaveField("Changes", ConsistOf(And(
+				HaveField("Action", v1alpha1.PlanActionChange),
+				HaveField("Fields", ContainElement("data.key")),
+			))))
+		configMap := &v1.ConfigMap{}
+		Expect(k8sClient.Get(ctx, configKey, configMap)).To(Succeed())
+		Expect(configMap.Data).To(HaveKeyWithValue("key", "other"))
+	})
+
+	It("should apply the resources and clear the plan once dry-run is disabled", func() {
+		Expect(k8sClient.Get(ctx, sampleCRKey, sampleCR)).To(Succeed())
+		sampleCR.Spec.DryRun = false
+		Expect(k8sClient.Update(ctx, sampleCR)).To(Succeed())
+
+		Eventually(getCRStatus(sampleCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Equal(CRStatus{State: v1alpha1.StateReady, InstallConditionStatus: metav1.ConditionTrue, Err: nil}))
+		Expect(getPlan(sampleCRKey)(Default)).To(BeNil())
+		configMap := &v1.ConfigMap{}
+		Expect(k8sClient.Get(ctx, configKey, configMap)).To(Succeed())
+		Expect(configMap.Data).To(HaveKeyWithValue("key", "value"))
+	})
+
+	It("should plan the deletion without deleting the resources in dry-run mode", func() {
+		Expect(k8sClient.Get(ctx, sampleCRKey, sampleCR)).To(Succeed())
+		sampleCR.Spec.DryRun = true
+		Expect(k8sClient.Update(ctx, sampleCR)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, sampleCR)).To(Succeed())
+
+		Eventually(getPlan(sampleCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(HaveField("Changes", ConsistOf(And(
+				HaveField("Action", v1alpha1.PlanActionDelete),
+				HaveField("Name", configKey.Name),
+			))))
+		Consistently(func() error {
+			return k8sClient.Get(ctx, configKey, &v1.ConfigMap{})
+		}).
+			WithTimeout(5 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Succeed())
+		Expect(k8sClient.Get(ctx, sampleCRKey, &v1alpha1.Sample{})).To(Succeed())
+	})
+
+	It("should delete the resources with the SampleCR once dry-run is disabled", func() {
+		Expect(k8sClient.Get(ctx, sampleCRKey, sampleCR)).To(Succeed())
+		sampleCR.Spec.DryRun = false
+		Expect(k8sClient.Update(ctx, sampleCR)).To(Succeed())
+		Eventually(func() bool {
+			return errors.IsNotFound(k8sClient.Get(ctx, configKey, &v1.ConfigMap{})) &&
+				errors.IsNotFound(k8sClient.Get(ctx, sampleCRKey, &v1alpha1.Sample{}))
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeTrue())
+	})
+})
+
+func getPlan(sampleObjKey client.ObjectKey) func(g Gomega) *v1alpha1.Plan {
+	return func(gomega Gomega) *v1alpha1.Plan {
+		sampleCR := &v1alpha1.Sample{}
+		gomega.Expect(k8sClient.Get(ctx, sampleObjKey, sampleCR)).To(Succeed())
+		return sampleCR.Status.Plan
+	}
+}
@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	errors2 "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+)
+
+// resourceApplier holds the state shared by all objects applied during one reconciliation of a Sample.
+type resourceApplier struct {
+	driftPolicy v1alpha1.DriftPolicy
+	// pendingCRDs are the CRDs of the manifest which are not yet known to be established
+	pendingCRDs map[schema.GroupKind]*unstructured.Unstructured
+	// previous contains the objects of the previous inventory, which are expected to exist
+	previous map[inventoryKey]struct{}
+	// drifts are the objects which drifted from the manifest
+	drifts []driftResult
+	// errors are the errors which occurred when applying objects
+	errors map[*unstructured.Unstructured]string
+}
+
+func newResourceApplier(objectInstance *v1alpha1.Sample,
+	resources []*unstructured.Unstructured,
+) *resourceApplier {
+	previous := make(map[inventoryKey]struct{}, len(objectInstance.Status.Inventory))
+	for _, entry := range objectInstance.Status.Inventory {
+		previous[getInventoryKey(entry)] = struct{}{}
+	}
+	driftPolicy := objectInstance.Spec.DriftPolicy
+	if driftPolicy == "" {
+		driftPolicy = v1alpha1.DriftPolicyRemediate
+	}
+	return &resourceApplier{
+		driftPolicy: driftPolicy,
+		pendingCRDs: getManifestCRDs(resources),
+		previous:    previous,
+		drifts:      make([]driftResult, 0),
+		errors:      map[*unstructured.Unstructured]string{},
+	}
+}
+
+// applyResources applies the objects in order and returns the inventory entries of the applied ones,
+// which is a partial inventory if applying fails.
+func (r *SampleReconciler) applyResources(ctx context.Context, applier *resourceApplier,
+	resources []*unstructured.Unstructured,
+) ([]v1alpha1.InventoryEntry, error) {
+	// the resources to be installed are unstructured,
+	// so please make sure the types are available on the target cluster
+	applied := make([]v1alpha1.InventoryEntry, 0, len(resources))
+	for _, obj := range resources {
+		if err := r.applyResource(ctx, applier, obj); err != nil {
+			if !errors.Is(err, errCRDNotEstablished) {
+				applier.errors[obj] = err.Error()
+				recordObjectError(applyErrorsTotal, obj, err)
+			}
+			return applied, err
+		}
+		applied = append(applied, newInventoryEntry(obj))
+	}
+	return applied, nil
+}
+
+// applyResource applies the object. If the kind of the object is defined by one of the pending CRDs
+// of the manifest, it is only applied once the CRD is established, which removes it from the pending CRDs.
+// Objects which drifted from the manifest are only applied with the Remediate DriftPolicy. With the Report
+// DriftPolicy, changes of the manifest are still applied, while the drifted fields keep their values.
+func (r *SampleReconciler) applyResource(ctx context.Context, applier *resourceApplier,
+	obj *unstructured.Unstructured,
+) error {
+	groupKind := obj.GroupVersionKind().GroupKind()
+	if crd, found := applier.pendingCRDs[groupKind]; found {
+		if err := r.checkCRDEstablished(ctx, crd); err != nil {
+			return err
+		}
+		delete(applier.pendingCRDs, groupKind)
+	}
+
+	live, err := r.getLiveObject(ctx, obj)
+	if err != nil {
+		return err
+	}
+	// objects which did not change since they were last applied without drift are neither checked nor applied
+	if r.driftChecks.isUnchanged(obj, live) {
+		return nil
+	}
+	_, expected := applier.previous[getInventoryKey(newInventoryEntry(obj))]
+	drift, err := r.detectDrift(ctx, obj, live, expected)
+	if err != nil {
+		return err
+	}
+	if drift != nil {
+		applier.drifts = append(applier.drifts, *drift)
+		if applier.driftPolicy == v1alpha1.DriftPolicyReport {
+			if !drift.manifestChanged {
+				return nil
+			}
+			obj = drift.withoutDriftedFields()
+		}
+	}
+
+	if err := ssa(ctx, r.Client, obj); err != nil && !errors2.IsAlreadyExists(err) {
+		return err
+	}
+	if drift == nil {
+		r.driftChecks.record(obj, live)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"cmp"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+)
+
+// setResourceStatuses reports the status of each object of the manifest in apply order.
+// If there are more objects than the MaxStatusResources of the Sample or the SampleReconciler,
+// the objects which failed or are not healthy are kept.
+func (r *SampleReconciler) setResourceStatuses(objectInstance *v1alpha1.Sample, status *v1alpha1.SampleStatus,
+	resources []*unstructured.Unstructured, applier *resourceApplier, results []healthResult,
+) {
+	health := make(map[*unstructured.Unstructured]healthResult, len(results))
+	for _, result := range results {
+		health[result.obj] = result
+	}
+
+	statuses := make([]v1alpha1.ResourceStatus, 0, len(resources))
+	for _, obj := range resources {
+		resource := v1alpha1.ResourceStatus{
+			InventoryEntry: newInventoryEntry(obj),
+			LastError:      applier.errors[obj],
+		}
+		if result, found := health[obj]; found {
+			resource.AppliedGeneration = result.generation
+			resource.Health = result.health
+			resource.Message = result.message
+		}
+		statuses = append(statuses, resource)
+	}
+
+	limit := r.getMaxStatusResources(objectInstance)
+	if len(statuses) <= limit {
+		status.WithResources(statuses, 0)
+		return
+	}
+	slices.SortStableFunc(statuses, func(a, b v1alpha1.ResourceStatus) int {
+		return cmp.Compare(getResourceStatusPriority(a), getResourceStatusPriority(b))
+	})
+	//nolint:gosec // the number of omitted objects is bounded by the size of the manifest
+	status.WithResources(statuses[:limit], int32(len(statuses)-limit))
+}
+
+// resourceHealthPriority orders the health of objects by how relevant it is, empty for objects not applied yet.
+//
+//nolint:gochecknoglobals // immutable order of health
+var resourceHealthPriority = []v1alpha1.Health{
+	v1alpha1.HealthDegraded, v1alpha1.HealthProgressing, "", v1alpha1.HealthHealthy,
+}
+
+// getResourceStatusPriority orders the objects by how relevant their status is,
+// so that failed objects and objects blocking readiness are listed first.
+func getResourceStatusPriority(resource v1alpha1.ResourceStatus) int {
+	if resource.LastError != "" {
+		return 0
+	}
+	return 1 + slices.Index(resourceHealthPriority, resource.Health)
+}
+
+func (r *SampleReconciler) getMaxStatusResources(objectInstance *v1alpha1.Sample) int {
+	if objectInstance.Spec.MaxStatusResources > 0 {
+		return int(objectInstance.Spec.MaxStatusResources)
+	}
+	if r.MaxStatusResources <= 0 {
+		return defaultMaxStatusResources
+	}
+	return r.MaxStatusResources
+}
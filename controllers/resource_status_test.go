@@ -0,0 +1,68 @@
+package controllers_test
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sample CR reports the status of each resource", Ordered, func() {
+	sampleCR := createSampleCR("resource-status-sample", "./test/resource-status")
+	sampleCRKey := client.ObjectKeyFromObject(sampleCR)
+	configName := "resource-status-config"
+	podName := "resource-status-pod"
+
+	It("should list the health of each resource", func() {
+		Expect(k8sClient.Create(ctx, sampleCR)).To(Succeed())
+
+		Eventually(getResourceStatuses(sampleCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(ConsistOf(
+				And(HaveField("Name", configName), HaveField("Health", v1alpha1.HealthHealthy)),
+				And(HaveField("Name", podName), HaveField("Health", v1alpha1.HealthProgressing),
+					HaveField("Message", "pod is not ready")),
+			))
+	})
+
+	It("should keep the resources blocking readiness if the list is capped", func() {
+		Expect(k8sClient.Get(ctx, sampleCRKey, sampleCR)).To(Succeed())
+		sampleCR.Spec.MaxStatusResources = 1
+		Expect(k8sClient.Update(ctx, sampleCR)).To(Succeed())
+
+		Eventually(getResourceStatuses(sampleCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(ConsistOf(HaveField("Name", podName)))
+		Expect(k8sClient.Get(ctx, sampleCRKey, sampleCR)).To(Succeed())
+		Expect(sampleCR.Status.OmittedResources).To(Equal(int32(1)))
+	})
+
+	It("should delete the resources with the SampleCR", func() {
+		Expect(k8sClient.Delete(ctx, sampleCR)).To(Succeed())
+		Eventually(func() bool {
+			return errors.IsNotFound(k8sClient.Get(ctx,
+				client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: podName}, &v1.Pod{})) &&
+				errors.IsNotFound(k8sClient.Get(ctx, sampleCRKey, &v1alpha1.Sample{}))
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeTrue())
+	})
+})
+
+func getResourceStatuses(sampleObjKey client.ObjectKey) func(g Gomega) []v1alpha1.ResourceStatus {
+	return func(gomega Gomega) []v1alpha1.ResourceStatus {
+		sampleCR := &v1alpha1.Sample{}
+		gomega.Expect(k8sClient.Get(ctx, sampleObjKey, sampleCR)).To(Succeed())
+		return sampleCR.Status.Resources
+	}
+}
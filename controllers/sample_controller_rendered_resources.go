@@ -18,26 +18,28 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"io/fs"
-	"os"
-	"path/filepath"
+	"time"
 
-	"github.com/go-logr/logr"
-	errors2 "k8s.io/apimachinery/pkg/api/errors"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	"github.com/kyma-project/template-operator/api/v1alpha1"
+	"github.com/kyma-project/template-operator/api/v1beta1"
 )
 
 // SampleReconciler reconciles a Sample object.
@@ -47,14 +49,32 @@ type SampleReconciler struct {
 	events.EventRecorder
 
 	Scheme *runtime.Scheme
-	// EventRecorder for creating k8s events
-	FinalState         v1alpha1.State
+	// FinalState is set once all resources are healthy, unless a Sample overrides it with spec.finalState
+	FinalState v1alpha1.State
+	// FinalDeletionState is set once a Sample is marked for deletion,
+	// unless it overrides it with spec.finalDeletionState
 	FinalDeletionState v1alpha1.State
+	// HealthCheckTimeout after which resources which are not healthy put the reconciled resource into Error state
+	HealthCheckTimeout time.Duration
+	// ResyncPeriod after which a Ready or Warning resource is reconciled, if none of its resources changed
+	ResyncPeriod time.Duration
+	// DryRun plans the changes of all reconciled resources, instead of applying them
+	DryRun bool
+	// MaxStatusResources caps the number of objects listed in the status of the reconciled resource
+	MaxStatusResources int
+	// DeletionBlockedState is set while the deletion of a Sample is blocked by custom resources of its CRDs
+	DeletionBlockedState v1alpha1.State
+
+	watcher     *resourceWatcher
+	driftChecks *driftChecks
 }
 
+var errManifestUnavailable = errors.New("neither inventory nor manifest of resources available")
+
 type ManifestResources struct {
 	Items []*unstructured.Unstructured
-	Blobs [][]byte
+	// Invalid contains an error for each document of the manifest which is not a valid object
+	Invalid []error
 }
 
 var (
@@ -69,12 +89,15 @@ var (
 
 func addKnownTypes(s *runtime.Scheme) error {
 	metav1.AddToGroupVersion(s, v1alpha1.GroupVersion)
+	metav1.AddToGroupVersion(s, v1beta1.GroupVersion)
 	return nil
 }
 
 func init() { //nolint:gochecknoinits // used to register Sample CRD on startup
 	SchemeBuilder.Register(func(s *runtime.Scheme) error {
-		s.AddKnownTypes(v1alpha1.GroupVersion, &v1alpha1.Sample{}, &v1alpha1.SampleList{})
+		s.AddKnownTypes(v1alpha1.GroupVersion, &v1alpha1.Sample{}, &v1alpha1.SampleList{},
+			&v1alpha1.Managed{}, &v1alpha1.ManagedList{}, &v1alpha1.ThirdParty{}, &v1alpha1.ThirdPartyList{})
+		s.AddKnownTypes(v1beta1.GroupVersion, &v1beta1.Sample{}, &v1beta1.SampleList{})
 		return nil
 	})
 }
@@ -84,16 +107,25 @@ func init() { //nolint:gochecknoinits // used to register Sample CRD on startup
 // +kubebuilder:rbac:groups=operator.kyma-project.io,resources=samples/finalizers,verbs=update
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch;get;list;watch
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups="",resources=configmaps,verbs=create;patch;delete
-// +kubebuilder:rbac:groups="apps",resources=deployments,verbs=create;patch;delete
-// +kubebuilder:rbac:groups="apps",resources=statefulsets,verbs=create;patch;delete
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;patch;delete
+// +kubebuilder:rbac:groups="apps",resources=deployments,verbs=get;list;watch;create;patch;delete
+// +kubebuilder:rbac:groups="apps",resources=statefulsets,verbs=get;list;watch;create;patch;delete
+// +kubebuilder:rbac:groups="apiextensions.k8s.io",resources=customresourcedefinitions,verbs=get;list;watch
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *SampleReconciler) SetupWithManager(mgr ctrl.Manager, rateLimiter RateLimiter) error {
 	r.Config = mgr.GetConfig()
 
-	if err := ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.Sample{}).
+	builder := ctrl.NewControllerManagedBy(mgr).For(&v1alpha1.Sample{})
+	installed, err := isThirdPartyInstalled(mgr)
+	if err != nil {
+		return err
+	}
+	if installed {
+		// the objects adopted through ThirdParties are updated as soon as the ThirdParties change
+		builder = builder.Watches(&v1alpha1.ThirdParty{}, handler.EnqueueRequestsFromMapFunc(getSampleRequestsOfThirdParty))
+	}
+	sampleController, err := builder.
 		WithOptions(controller.Options{
 			RateLimiter: TemplateRateLimiter(
 				rateLimiter.BaseDelay,
@@ -102,15 +134,32 @@ func (r *SampleReconciler) SetupWithManager(mgr ctrl.Manager, rateLimiter RateLi
 				rateLimiter.Burst,
 			),
 		}).
-		Complete(r); err != nil {
+		Build(r)
+	if err != nil {
 		return fmt.Errorf("error while setting up controller: %w", err)
 	}
+	// the kinds of the applied resources are only known at runtime, so they are watched once they are applied
+	r.watcher = newResourceWatcher(sampleController, mgr.GetCache(), newSampleHandler,
+		predicate.NewPredicateFuncs(hasSampleLabels))
+	r.driftChecks = newDriftChecks()
+	if err = registerSampleCollector(mgr.GetCache()); err != nil {
+		return fmt.Errorf("error while registering metrics: %w", err)
+	}
 	return nil
 }
 
 // Reconcile is the entry point from the controller-runtime framework.
-// It performs a reconciliation based on the passed ctrl.Request object.
+// It performs a reconciliation based on the passed ctrl.Request object, traced in a span.
 func (r *SampleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := startSpan(ctx, "Reconcile",
+		attributeSampleName.String(req.Name), attributeSampleNamespace.String(req.Namespace))
+	result, err := r.reconcile(ctx, req)
+	span.SetAttributes(attributeRequeueAfter.String(result.RequeueAfter.String()))
+	endSpan(span, err)
+	return result, err
+}
+
+func (r *SampleReconciler) reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 
 	objectInstance := v1alpha1.Sample{}
@@ -125,33 +174,72 @@ func (r *SampleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		}
 		return ctrl.Result{}, nil
 	}
+	trace.SpanFromContext(ctx).SetAttributes(attributeSampleState.String(string(objectInstance.Status.State)))
+
+	// a suspended Sample only reports the health of its resources, also if it is marked for deletion
+	if message := getSuspendMessage(&objectInstance); message != "" {
+		return ctrl.Result{RequeueAfter: r.getReadyRequeueInterval(&objectInstance)},
+			traceState(ctx, "HandleSuspendedState", &objectInstance,
+				func(ctx context.Context, objectInstance *v1alpha1.Sample) error {
+					return r.HandleSuspendedState(ctx, objectInstance, message)
+				})
+	}
+	if isMarkedSuspended(&objectInstance) {
+		return ctrl.Result{Requeue: true}, traceState(ctx, "HandleResumedState", &objectInstance, r.HandleResumedState)
+	}
 
 	// check if deletionTimestamp is set, retry until it gets deleted
 	status := getStatusFromSample(&objectInstance)
 
-	// set state to FinalDeletionState (default is Deleting) if not set for an object with deletion timestamp
-	if !objectInstance.GetDeletionTimestamp().IsZero() && status.State != r.FinalDeletionState {
-		return ctrl.Result{}, r.setStatusForObjectInstance(ctx, &objectInstance, status.WithState(r.FinalDeletionState))
+	if !objectInstance.GetDeletionTimestamp().IsZero() {
+		blocked, err := r.isDeletionBlocked(ctx, &objectInstance, &status)
+		if blocked || err != nil {
+			return ctrl.Result{RequeueAfter: deletionBlockedRequeueInterval}, err
+		}
+	}
+
+	// set state to FinalDeletionState (default is Deleting) if not set for an object with deletion timestamp,
+	// or once its deletion is no longer blocked
+	finalDeletionState := r.getFinalDeletionState(&objectInstance)
+	if !objectInstance.GetDeletionTimestamp().IsZero() &&
+		(status.State != finalDeletionState || wasDeletionBlocked(&status)) {
+		return ctrl.Result{}, r.setStatusForObjectInstance(ctx, &objectInstance, status.
+			WithState(finalDeletionState).
+			WithDeletingCondition(v1alpha1.ConditionReasonDeleting, "deleting resources", objectInstance.GetGeneration()))
 	}
 
 	if objectInstance.GetDeletionTimestamp().IsZero() {
 		// add finalizer if not present
 		if controllerutil.AddFinalizer(&objectInstance, finalizer) {
-			return ctrl.Result{}, r.ssa(ctx, &objectInstance)
+			return ctrl.Result{}, ssa(ctx, r.Client, &objectInstance)
+		}
+		// the state of a Sample with a scenario is scripted, until it is deleted
+		if objectInstance.Spec.Scenario != nil || objectInstance.Status.Scenario != nil {
+			var requeueAfter time.Duration
+			err := traceState(ctx, "HandleScenarioState", &objectInstance,
+				func(ctx context.Context, objectInstance *v1alpha1.Sample) error {
+					var err error
+					requeueAfter, err = r.HandleScenarioState(ctx, objectInstance)
+					return err
+				})
+			return ctrl.Result{RequeueAfter: requeueAfter}, err
 		}
 	}
 
 	switch status.State {
 	case "":
-		return ctrl.Result{}, r.HandleInitialState(ctx, &objectInstance)
+		return ctrl.Result{}, traceState(ctx, "HandleInitialState", &objectInstance, r.HandleInitialState)
 	case v1alpha1.StateProcessing:
-		return ctrl.Result{Requeue: true}, r.HandleProcessingState(ctx, &objectInstance)
+		return ctrl.Result{RequeueAfter: requeueInterval},
+			traceState(ctx, "HandleProcessingState", &objectInstance, r.HandleProcessingState)
 	case v1alpha1.StateDeleting:
-		return ctrl.Result{Requeue: true}, r.HandleDeletingState(ctx, &objectInstance)
+		return ctrl.Result{Requeue: true},
+			traceState(ctx, "HandleDeletingState", &objectInstance, r.HandleDeletingState)
 	case v1alpha1.StateError:
-		return ctrl.Result{Requeue: true}, r.HandleErrorState(ctx, &objectInstance)
+		return ctrl.Result{Requeue: true}, traceState(ctx, "HandleErrorState", &objectInstance, r.HandleErrorState)
 	case v1alpha1.StateReady, v1alpha1.StateWarning:
-		return ctrl.Result{RequeueAfter: requeueInterval}, r.HandleReadyState(ctx, &objectInstance)
+		return ctrl.Result{RequeueAfter: r.getReadyRequeueInterval(&objectInstance)},
+			traceState(ctx, "HandleReadyState", &objectInstance, r.HandleReadyState)
 	}
 
 	return ctrl.Result{}, nil
@@ -160,48 +248,49 @@ func (r *SampleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 // HandleInitialState bootstraps state handling for the reconciled resource.
 func (r *SampleReconciler) HandleInitialState(ctx context.Context, objectInstance *v1alpha1.Sample) error {
 	status := getStatusFromSample(objectInstance)
+	setProgressingConditions(&status, v1alpha1.ConditionReasonInstalling, "installing resources",
+		objectInstance.GetGeneration())
 
-	return r.setStatusForObjectInstance(ctx, objectInstance, status.
-		WithState(v1alpha1.StateProcessing).
-		WithInstallConditionStatus(metav1.ConditionUnknown, objectInstance.GetGeneration()))
+	return r.setStatusForObjectInstance(ctx, objectInstance, status.WithState(v1alpha1.StateProcessing))
 }
 
 // HandleProcessingState processes the reconciled resource by processing the underlying resources.
 // Based on the processing either a success or failure state is set on the reconciled resource.
 func (r *SampleReconciler) HandleProcessingState(ctx context.Context, objectInstance *v1alpha1.Sample) error {
 	status := getStatusFromSample(objectInstance)
-	if err := r.processResources(ctx, objectInstance); err != nil {
+	if err := r.processResources(ctx, objectInstance, &status); err != nil {
 		// stay in Processing state if FinalDeletionState is set to Processing
-		if !objectInstance.GetDeletionTimestamp().IsZero() && r.FinalDeletionState == v1alpha1.StateProcessing {
+		if !objectInstance.GetDeletionTimestamp().IsZero() && r.getFinalDeletionState(objectInstance) == v1alpha1.StateProcessing {
 			return nil
 		}
 
 		r.Eventf(objectInstance, nil, "Warning", "ResourcesInstall", "Processing", "%v", err)
-		return r.setStatusForObjectInstance(ctx, objectInstance, status.
-			WithState(v1alpha1.StateError).
-			WithInstallConditionStatus(metav1.ConditionFalse, objectInstance.GetGeneration()))
+		setFailedConditions(&status, getConditionReason(err), err.Error(), objectInstance.GetGeneration())
+		return r.setStatusForObjectInstance(ctx, objectInstance, status.WithState(v1alpha1.StateError))
 	}
-	// set eventual state to Ready - if no errors were found
-	return r.setStatusForObjectInstance(ctx, objectInstance, status.
-		WithState(r.FinalState).
-		WithInstallConditionStatus(metav1.ConditionTrue, objectInstance.GetGeneration()))
+	// set eventual state to Ready - once all resources are healthy
+	return r.setStatusFromHealth(ctx, objectInstance, &status)
 }
 
 // HandleErrorState handles error recovery for the reconciled resource.
 func (r *SampleReconciler) HandleErrorState(ctx context.Context, objectInstance *v1alpha1.Sample) error {
 	status := getStatusFromSample(objectInstance)
-	if err := r.processResources(ctx, objectInstance); err != nil {
-		return err
+	if err := r.processResources(ctx, objectInstance, &status); err != nil {
+		setFailedConditions(&status, getConditionReason(err), err.Error(), objectInstance.GetGeneration())
+		return errors.Join(err, r.setStatusIfChanged(ctx, objectInstance, &status))
 	}
 
 	// stay in Error state if FinalDeletionState is set to Error
-	if !objectInstance.GetDeletionTimestamp().IsZero() && r.FinalDeletionState == v1alpha1.StateError {
+	if !objectInstance.GetDeletionTimestamp().IsZero() && r.getFinalDeletionState(objectInstance) == v1alpha1.StateError {
 		return nil
 	}
+	// stay in Error state until all resources are healthy, unless a new generation restarts the health check
+	if !isHealthy(&status) && !r.isDryRun(objectInstance) &&
+		status.GenerationObservedTime.Equal(objectInstance.Status.GenerationObservedTime) {
+		return r.setStatusIfChanged(ctx, objectInstance, &status)
+	}
 	// set eventual state to Ready - if no errors were found
-	return r.setStatusForObjectInstance(ctx, objectInstance, status.
-		WithState(r.FinalState).
-		WithInstallConditionStatus(metav1.ConditionTrue, objectInstance.GetGeneration()))
+	return r.setStatusFromHealth(ctx, objectInstance, &status)
 }
 
 // HandleDeletingState processed the deletion on the reconciled resource.
@@ -212,39 +301,76 @@ func (r *SampleReconciler) HandleDeletingState(ctx context.Context, objectInstan
 
 	status := getStatusFromSample(objectInstance)
 
-	resourceObjs, err := getResourcesFromLocalPath(objectInstance.Spec.ResourceFilePath, logger)
-	if err != nil && controllerutil.RemoveFinalizer(objectInstance, finalizer) {
+	inventory, err := r.getDeletionInventory(ctx, objectInstance, &status)
+	if errors.Is(err, errManifestUnavailable) {
 		// if error is encountered simply remove the finalizer and delete the reconciled resource
-		if err := r.Update(ctx, objectInstance); err != nil {
-			return fmt.Errorf("error while removing finalizer: %w", err)
-		}
-		return nil
+		return r.removeFinalizer(ctx, objectInstance)
+	}
+	if err != nil {
+		return err
+	}
+
+	// in dry-run mode the resources are left untouched, and the Sample is kept until dry-run is disabled
+	if r.isDryRun(objectInstance) {
+		return r.recordDeletionPlan(ctx, objectInstance, &status, inventory)
 	}
 	r.Eventf(objectInstance, nil, "Normal", "ResourcesDelete", "Deleting", "deleting resources")
 
-	// the resources to be installed are unstructured,
-	// so please make sure the types are available on the target cluster
-	for _, obj := range resourceObjs.Items {
-		if err = r.Delete(ctx, obj); err != nil && !errors2.IsNotFound(err) {
+	// the resources to be deleted are unstructured,
+	// so please make sure the types are available on the target cluster.
+	// They are deleted in reverse apply order, so that e.g. custom resources are deleted before their CRDs,
+	// unless their DeletionPolicy keeps them
+	for i := len(inventory) - 1; i >= 0; i-- {
+		if err := r.deleteResource(ctx, objectInstance, inventory[i]); err != nil {
 			// stay in Deleting state if FinalDeletionState is set to Deleting
-			if !objectInstance.GetDeletionTimestamp().IsZero() && r.FinalDeletionState == v1alpha1.StateDeleting {
+			if !objectInstance.GetDeletionTimestamp().IsZero() && r.getFinalDeletionState(objectInstance) == v1alpha1.StateDeleting {
 				return nil
 			}
 
 			logger.Error(err, "error during uninstallation of resources")
 			r.Eventf(objectInstance, nil, "Warning", "ResourcesDelete", "Deleting", "deleting resources error")
+			message := fmt.Sprintf("error during uninstallation of resources: %v", err)
 			return r.setStatusForObjectInstance(ctx, objectInstance, status.
 				WithState(v1alpha1.StateError).
-				WithInstallConditionStatus(metav1.ConditionFalse, objectInstance.GetGeneration()))
+				WithDeletingCondition(v1alpha1.ConditionReasonDeletionFailed, message, objectInstance.GetGeneration()).
+				WithCondition(v1alpha1.ConditionTypeDegraded, metav1.ConditionTrue,
+					v1alpha1.ConditionReasonDeletionFailed, message, objectInstance.GetGeneration()))
 		}
 	}
 
 	// if resources are ready to be deleted, remove finalizer
+	return r.removeFinalizer(ctx, objectInstance)
+}
+
+// getDeletionInventory returns the objects to be deleted with the Sample, in apply order.
+// The objects adopted through ThirdParties at the time of the deletion are deleted first.
+func (r *SampleReconciler) getDeletionInventory(ctx context.Context, objectInstance *v1alpha1.Sample,
+	status *v1alpha1.SampleStatus,
+) ([]v1alpha1.InventoryEntry, error) {
+	// the inventory is recorded in apply order
+	inventory := status.Inventory
+	if len(inventory) == 0 {
+		// resources installed before the inventory was recorded are deleted based on the current manifest
+		resourceObjs, err := r.loadManifestResources(ctx, objectInstance)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errManifestUnavailable, err)
+		}
+		sortResourcesForApply(resourceObjs.Items)
+		inventory = getInventoryFromResources(resourceObjs.Items)
+	}
+	adopted, err := getAdoptedInventory(ctx, r.Client, objectInstance)
+	if err != nil {
+		return nil, err
+	}
+	return mergeInventory(inventory, adopted), nil
+}
+
+func (r *SampleReconciler) removeFinalizer(ctx context.Context, objectInstance *v1alpha1.Sample) error {
 	if controllerutil.RemoveFinalizer(objectInstance, finalizer) {
 		if err := r.Update(ctx, objectInstance); err != nil {
 			return fmt.Errorf("error while removing finalizer: %w", err)
 		}
-		return nil
+		forgetDrifts(objectInstance)
 	}
 	return nil
 }
@@ -252,108 +378,286 @@ func (r *SampleReconciler) HandleDeletingState(ctx context.Context, objectInstan
 // HandleReadyState checks for the consistency of reconciled resource, by verifying the underlying resources.
 func (r *SampleReconciler) HandleReadyState(ctx context.Context, objectInstance *v1alpha1.Sample) error {
 	status := getStatusFromSample(objectInstance)
-	if err := r.processResources(ctx, objectInstance); err != nil {
+	if err := r.processResources(ctx, objectInstance, &status); err != nil {
 		// stay in Ready/Warning state if FinalDeletionState is set to Ready/Warning
 		if !objectInstance.GetDeletionTimestamp().IsZero() &&
-			(r.FinalDeletionState == v1alpha1.StateReady || r.FinalDeletionState == v1alpha1.StateWarning) {
+			(r.getFinalDeletionState(objectInstance) == v1alpha1.StateReady || r.getFinalDeletionState(objectInstance) == v1alpha1.StateWarning) {
 			return nil
 		}
 
 		r.Eventf(objectInstance, nil, "Warning", "ResourcesInstall", "Processing", "%v", err)
-		return r.setStatusForObjectInstance(ctx, objectInstance, status.
-			WithState(v1alpha1.StateError).
-			WithInstallConditionStatus(metav1.ConditionFalse, objectInstance.GetGeneration()))
+		setFailedConditions(&status, getConditionReason(err), err.Error(), objectInstance.GetGeneration())
+		return r.setStatusForObjectInstance(ctx, objectInstance, status.WithState(v1alpha1.StateError))
 	}
-	return nil
+	return r.setStatusFromHealth(ctx, objectInstance, &status)
 }
 
 func (r *SampleReconciler) setStatusForObjectInstance(ctx context.Context, objectInstance *v1alpha1.Sample,
 	status *v1alpha1.SampleStatus,
 ) error {
+	previousState := objectInstance.Status.State
 	objectInstance.Status = *status
 
-	if err := r.ssaStatus(ctx, objectInstance); err != nil {
+	if err := ssaStatus(ctx, r.Client, objectInstance); err != nil {
 		r.Eventf(objectInstance, nil, "Warning", "ErrorUpdatingStatus", "UpdatingStatus", "updating state to %v",
 			string(status.State))
 		return fmt.Errorf("error while updating status %s to: %w", status.State, err)
 	}
+	recordStateTransition(previousState, status.State)
 
 	r.Eventf(objectInstance, nil, "Normal", "StatusUpdated", "UpdatingStatus", "updating state to %v",
 		string(status.State))
 	return nil
 }
 
-func (r *SampleReconciler) processResources(ctx context.Context, objectInstance *v1alpha1.Sample) error {
+func (r *SampleReconciler) processResources(ctx context.Context, objectInstance *v1alpha1.Sample,
+	status *v1alpha1.SampleStatus,
+) error {
 	logger := log.FromContext(ctx)
 
-	resourceObjs, err := getResourcesFromLocalPath(objectInstance.Spec.ResourceFilePath, logger)
+	resourceObjs, err := r.loadManifestResources(ctx, objectInstance)
 	if err != nil {
 		logger.Error(err, "error locating manifest of resources")
-		return fmt.Errorf("error locating manifest of resources: %w", err)
+		return withConditionReason(v1alpha1.ConditionReasonManifestNotFound,
+			fmt.Errorf("error locating manifest of resources: %w", err))
+	}
+	if err = r.checkManifestDocuments(objectInstance, status, resourceObjs); err != nil {
+		logger.Error(err, "error parsing manifest of resources")
+		return withConditionReason(v1alpha1.ConditionReasonManifestInvalid, err)
+	}
+
+	// the resources are applied by their apply wave and the priority of their kind,
+	// e.g. Namespaces and CRDs before the objects depending on them
+	sortResourcesForApply(resourceObjs.Items)
+	waves, err := splitApplyWaves(resourceObjs.Items)
+	if err != nil {
+		logger.Error(err, "error ordering resources")
+		return withConditionReason(v1alpha1.ConditionReasonManifestInvalid,
+			fmt.Errorf("error ordering resources: %w", err))
+	}
+
+	if err = validateDeletionPolicies(resourceObjs.Items); err != nil {
+		logger.Error(err, "error validating deletion policies of resources")
+		return withConditionReason(v1alpha1.ConditionReasonManifestInvalid, err)
+	}
+
+	setSampleLabels(resourceObjs.Items, objectInstance)
+	if err = setAppliedHashes(resourceObjs.Items); err != nil {
+		return withConditionReason(v1alpha1.ConditionReasonManifestInvalid, err)
+	}
+	if r.isDryRun(objectInstance) {
+		return r.recordPlan(ctx, objectInstance, status, resourceObjs.Items)
 	}
+	status.WithPlan(nil)
 
 	r.Eventf(objectInstance, nil, "Normal", "ResourcesInstall", "Processing", "installing resources")
 
-	// the resources to be installed are unstructured,
-	// so please make sure the types are available on the target cluster
-	for _, obj := range resourceObjs.Items {
-		if err = r.ssa(ctx, obj); err != nil && !errors2.IsAlreadyExists(err) {
+	applier := newResourceApplier(objectInstance, resourceObjs.Items)
+	applied := make([]v1alpha1.InventoryEntry, 0, len(resourceObjs.Items))
+	results := make([]healthResult, 0, len(resourceObjs.Items))
+	defer func() { r.setResourceStatuses(objectInstance, status, resourceObjs.Items, applier, results) }()
+	// dry-run reconciliations returned above, so only actual applies are recorded
+	start := time.Now()
+	defer func() { recordApply(objectInstance, time.Since(start), len(applied)) }()
+	for i, wave := range waves {
+		status.WithCurrentWave(wave.wave)
+		waveApplied, err := r.applyResources(ctx, applier, wave.resources)
+		applied = append(applied, waveApplied...)
+		if errors.Is(err, errCRDNotEstablished) {
+			// the remaining resources are applied with the next reconciliation, without blocking this one
+			status.WithInventory(mergeInventory(applied, status.Inventory))
+			r.setDriftCondition(objectInstance, status, applier)
+			status.WithHealthCondition(v1alpha1.HealthProgressing, err.Error(), objectInstance.GetGeneration())
+			return nil
+		}
+		if err != nil {
+			// keep track of everything applied so far, so that it can still be pruned or deleted
+			status.WithInventory(mergeInventory(applied, status.Inventory))
 			logger.Error(err, "error during installation of resources")
-			return fmt.Errorf("error during installation of resources: %w", err)
+			return withConditionReason(v1alpha1.ConditionReasonApplyFailed,
+				fmt.Errorf("error during installation of resources: %w", err))
+		}
+		if err = r.watcher.watchResources(wave.resources); err != nil {
+			logger.Error(err, "error during watching of resources")
+			return err
+		}
+
+		waveResults, err := checkResourcesHealth(ctx, r.Client, wave.resources, objectInstance.Spec.HealthChecks)
+		if err != nil {
+			logger.Error(err, "error during health check of resources")
+			return withConditionReason(v1alpha1.ConditionReasonHealthCheckFailed, err)
 		}
+		results = append(results, waveResults...)
+
+		// the following waves are applied once all resources of this wave are healthy,
+		// until then the resources of the previous inventory are not pruned
+		if health, message := aggregateHealth(waveResults); health != v1alpha1.HealthHealthy && i < len(waves)-1 {
+			status.WithInventory(mergeInventory(applied, status.Inventory))
+			r.setDriftCondition(objectInstance, status, applier)
+			status.WithHealthCondition(health, fmt.Sprintf("waiting for apply wave %d: %s", wave.wave, message),
+				objectInstance.GetGeneration())
+			return nil
+		}
+	}
+
+	r.setDriftCondition(objectInstance, status, applier)
+	inventory, err := r.pruneResources(ctx, objectInstance, status.Inventory, applied)
+	status.WithInventory(inventory)
+	if err != nil {
+		logger.Error(err, "error during pruning of resources")
+		return withConditionReason(v1alpha1.ConditionReasonPruneFailed, err)
+	}
+	adopted, err := getAdoptedInventory(ctx, r.Client, objectInstance)
+	if err != nil {
+		return err
 	}
+	status.WithAdoptedInventory(adopted)
+
+	health, message := aggregateHealth(results)
+	status.WithHealthCondition(health, message, objectInstance.GetGeneration())
 	return nil
 }
 
 func getStatusFromSample(objectInstance *v1alpha1.Sample) v1alpha1.SampleStatus {
-	return objectInstance.Status
+	return *objectInstance.Status.DeepCopy()
 }
 
-// getResourcesFromLocalPath returns resources from the dirPath in unstructured format.
-// Only one file in .yaml or .yml format should be present in the target directory.
-func getResourcesFromLocalPath(dirPath string, logger logr.Logger) (*ManifestResources, error) {
-	dirEntries := make([]fs.DirEntry, 0)
-	err := filepath.WalkDir(dirPath, func(path string, info fs.DirEntry, err error) error {
-		// initial error
-		if err != nil {
-			return fmt.Errorf("error while walkdir %s: %w", dirPath, err)
-		}
-		if !info.IsDir() {
-			return nil
+// setStatusIfChanged updates the status of the reconciled resource only if it differs from the persisted one.
+func (r *SampleReconciler) setStatusIfChanged(ctx context.Context, objectInstance *v1alpha1.Sample,
+	status *v1alpha1.SampleStatus,
+) error {
+	if equality.Semantic.DeepEqual(objectInstance.Status, *status) {
+		return nil
+	}
+	return r.setStatusForObjectInstance(ctx, objectInstance, status)
+}
+
+// setStatusFromHealth sets the state of the reconciled resource based on the health of the applied resources.
+// For a reconciled resource marked for deletion the state is kept, as it is determined by FinalDeletionState.
+func (r *SampleReconciler) setStatusFromHealth(ctx context.Context, objectInstance *v1alpha1.Sample,
+	status *v1alpha1.SampleStatus,
+) error {
+	if objectInstance.GetDeletionTimestamp().IsZero() {
+		state := r.getFinalState(objectInstance)
+		if r.isDryRun(objectInstance) {
+			// nothing is installed in dry-run mode, the plan is ready to be reviewed
+			setDryRunConditions(status, objectInstance.GetGeneration())
+		} else {
+			state = r.setConditionsFromHealth(status, state, objectInstance.GetGeneration())
 		}
-		dirEntries, err = os.ReadDir(dirPath)
-		if err != nil {
-			return fmt.Errorf("error while reading directory %s: %w", dirPath, err)
+		if state == v1alpha1.StateError && objectInstance.Status.State != v1alpha1.StateError {
+			r.Eventf(objectInstance, nil, "Warning", "HealthCheckTimeout", "Processing",
+				"resources did not become healthy within %v", r.getHealthCheckTimeout())
 		}
-		return nil
-	})
-	if err != nil {
-		return nil, fmt.Errorf("error while walkdir %s: %w", dirPath, err)
+		status.WithState(state)
 	}
+	return r.setStatusIfChanged(ctx, objectInstance, status)
+}
 
-	childCount := len(dirEntries)
-	if childCount == 0 {
-		logger.V(debugLogLevel).Info("no yaml file found at file path" + dirPath)
-		return nil, nil //nolint:nilnil // nil is returned if no yaml file is found
-	} else if childCount > 1 {
-		logger.V(debugLogLevel).Info("more than one yaml file found at file path" + dirPath)
-		return nil, nil //nolint:nilnil // nil is returned if more than one yaml file is found
+// setConditionsFromHealth sets the conditions based on the Healthy condition and returns the matching state.
+// Healthy resources result in the finalState, degraded resources in Warning and progressing resources in Processing,
+// until the resources of the current generation are not healthy for longer than the HealthCheckTimeout.
+func (r *SampleReconciler) setConditionsFromHealth(status *v1alpha1.SampleStatus, finalState v1alpha1.State,
+	objGeneration int64,
+) v1alpha1.State {
+	condition := meta.FindStatusCondition(status.Conditions, v1alpha1.ConditionTypeHealthy)
+	if condition == nil || condition.Status == metav1.ConditionTrue {
+		setReadyConditions(status, objGeneration)
+		return finalState
 	}
-	file := dirEntries[0]
-	allowedExtns := sets.NewString(".yaml", ".yml")
-	if !allowedExtns.Has(filepath.Ext(file.Name())) {
-		return nil, nil //nolint:nilnil // nil is returned if file is not in yaml format
+	if getUnhealthyDuration(status, condition) > r.getHealthCheckTimeout() {
+		setFailedConditions(status, v1alpha1.ConditionReasonHealthCheckTimeout,
+			fmt.Sprintf("resources did not become healthy within %v: %s", r.getHealthCheckTimeout(),
+				condition.Message), objGeneration)
+		return v1alpha1.StateError
 	}
+	if condition.Reason == string(v1alpha1.HealthDegraded) {
+		setFailedConditions(status, v1alpha1.ConditionReasonResourcesDegraded, condition.Message, objGeneration)
+		return v1alpha1.StateWarning
+	}
+	setProgressingConditions(status, v1alpha1.ConditionReasonWaitingForResources, condition.Message, objGeneration)
+	return v1alpha1.StateProcessing
+}
 
-	fileBytes, err := os.ReadFile(filepath.Join(dirPath, file.Name()))
-	if err != nil {
-		return nil, fmt.Errorf("yaml file could not be read %s in dir %s: %w", file.Name(), dirPath, err)
+// getReadyRequeueInterval returns the interval after which a Ready or Warning resource is reconciled again.
+// Changes of the applied resources trigger a reconciliation through their watches, so only a resync is needed.
+// Resources marked for deletion keep being polled, as their state is determined by FinalDeletionState.
+func (r *SampleReconciler) getReadyRequeueInterval(objectInstance *v1alpha1.Sample) time.Duration {
+	if !objectInstance.GetDeletionTimestamp().IsZero() {
+		return requeueInterval
 	}
-	return parseManifestStringToObjects(string(fileBytes))
+	return getResyncPeriod(r.ResyncPeriod)
+}
+
+// getResyncPeriod returns the period after which a Ready object is reconciled, if none of its objects changed.
+func getResyncPeriod(resyncPeriod time.Duration) time.Duration {
+	if resyncPeriod <= 0 {
+		return defaultResyncPeriod
+	}
+	return resyncPeriod
+}
+
+// getFinalState returns the state of the Sample once all its resources are healthy.
+func (r *SampleReconciler) getFinalState(objectInstance *v1alpha1.Sample) v1alpha1.State {
+	if objectInstance.Spec.FinalState != "" {
+		return objectInstance.Spec.FinalState
+	}
+	return r.FinalState
+}
+
+// getFinalDeletionState returns the state of the Sample once it is marked for deletion.
+func (r *SampleReconciler) getFinalDeletionState(objectInstance *v1alpha1.Sample) v1alpha1.State {
+	if objectInstance.Spec.FinalDeletionState != "" {
+		return objectInstance.Spec.FinalDeletionState
+	}
+	return r.FinalDeletionState
+}
+
+func (r *SampleReconciler) getHealthCheckTimeout() time.Duration {
+	if r.HealthCheckTimeout <= 0 {
+		return defaultHealthCheckTimeout
+	}
+	return r.HealthCheckTimeout
+}
+
+// getUnhealthyDuration returns for how long the resources of the current generation are not healthy,
+// measured from the time they became unhealthy or the generation was first observed, whichever is later.
+func getUnhealthyDuration(status *v1alpha1.SampleStatus, condition *metav1.Condition) time.Duration {
+	since := condition.LastTransitionTime.Time
+	if observed := status.GenerationObservedTime; observed != nil && observed.After(since) {
+		since = observed.Time
+	}
+	return time.Since(since)
+}
+
+func isHealthy(status *v1alpha1.SampleStatus) bool {
+	condition := meta.FindStatusCondition(status.Conditions, v1alpha1.ConditionTypeHealthy)
+	return condition == nil || condition.Status == metav1.ConditionTrue
+}
+
+// updateStatusIfChanged sets the persisted status of the object to the status and patches it using SSA,
+// only if the status differs from the persisted one.
+func updateStatusIfChanged[S any](ctx context.Context, c client.Client, obj client.Object, persisted, status *S,
+) error {
+	if equality.Semantic.DeepEqual(*persisted, *status) {
+		return nil
+	}
+	*persisted = *status
+	if err := ssaStatus(ctx, c, obj); err != nil {
+		return fmt.Errorf("error while updating status: %w", err)
+	}
+	return nil
 }
 
 // ssaStatus patches status using SSA on the passed object.
-func (r *SampleReconciler) ssaStatus(ctx context.Context, obj client.Object) error {
+func ssaStatus(ctx context.Context, c client.Client, obj client.Object) error {
+	ctx, span := startSpan(ctx, "ApplyStatus", objectAttributes(c, obj)...)
+	err := applyObjectStatus(ctx, c, obj)
+	endSpan(span, err)
+	return err
+}
+
+func applyObjectStatus(ctx context.Context, c client.Client, obj client.Object) error {
 	obj.SetManagedFields(nil)
 	obj.SetResourceVersion("")
 
@@ -362,14 +666,21 @@ func (r *SampleReconciler) ssaStatus(ctx context.Context, obj client.Object) err
 		return fmt.Errorf("error getting apply configuration for object: %w", err)
 	}
 
-	if err := r.SubResource("status").Apply(ctx, applyConfig, client.FieldOwner(fieldOwner)); err != nil {
+	if err := c.SubResource("status").Apply(ctx, applyConfig, client.FieldOwner(fieldOwner)); err != nil {
 		return fmt.Errorf("error while patching status: %w", err)
 	}
 	return nil
 }
 
 // ssa patches the object using SSA.
-func (r *SampleReconciler) ssa(ctx context.Context, obj client.Object) error {
+func ssa(ctx context.Context, c client.Client, obj client.Object) error {
+	ctx, span := startSpan(ctx, "Apply", objectAttributes(c, obj)...)
+	err := applyObject(ctx, c, obj)
+	endSpan(span, err)
+	return err
+}
+
+func applyObject(ctx context.Context, c client.Client, obj client.Object) error {
 	obj.SetManagedFields(nil)
 	obj.SetResourceVersion("")
 
@@ -378,7 +689,7 @@ func (r *SampleReconciler) ssa(ctx context.Context, obj client.Object) error {
 		return fmt.Errorf("error getting apply configuration for object: %w", err)
 	}
 
-	if err := r.Apply(ctx, applyConfig, client.ForceOwnership, client.FieldOwner(fieldOwner)); err != nil {
+	if err := c.Apply(ctx, applyConfig, client.ForceOwnership, client.FieldOwner(fieldOwner)); err != nil {
 		return fmt.Errorf("error while patching object: %w", err)
 	}
 	return nil
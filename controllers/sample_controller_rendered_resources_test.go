@@ -3,12 +3,18 @@ package controllers_test
 import (
 	"time"
 
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	"github.com/kyma-project/template-operator/api/v1alpha1"
 
@@ -24,23 +30,59 @@ var (
 var _ = Describe("Sample CR is created with the correct resource path", Ordered, func() {
 	sampleCR := createSampleCR("valid-sample", "./test/busybox/manifest")
 	sampleCRKey := client.ObjectKeyFromObject(sampleCR)
+	recorder := tracetest.NewSpanRecorder()
+
+	BeforeAll(func() {
+		DeferCleanup(otel.SetTracerProvider, otel.GetTracerProvider())
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	})
 
 	It("should create SampleCR and resources", func() {
 		Expect(k8sClient.Create(ctx, sampleCR)).To(Succeed())
 
+		Eventually(getPod(podNs, podName)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeTrue())
+
 		Eventually(getCRStatus(sampleCRKey)).
 			WithTimeout(30 * time.Second).
 			WithPolling(500 * time.Millisecond).
 			Should(Equal(CRStatus{State: v1alpha1.StateReady, InstallConditionStatus: metav1.ConditionTrue, Err: nil}))
+	})
 
-		Eventually(getPod(podNs, podName)).
+	It("should record the states and the applied objects in the metrics", func() {
+		Eventually(getMetricValue("template_operator_samples", map[string]string{"state": "Ready"})).
 			WithTimeout(30 * time.Second).
 			WithPolling(500 * time.Millisecond).
-			Should(BeTrue())
+			Should(BeNumerically(">=", 1))
+		Expect(getMetricValue("template_operator_sample_state_transitions_total",
+			map[string]string{"from": "Processing", "to": "Ready"})(Default)).To(BeNumerically(">=", 1))
+		Expect(getMetricValue("template_operator_sample_applied_objects",
+			map[string]string{"source": "resourceFilePath"})(Default)).To(BeNumerically(">=", 1))
+	})
+
+	It("should record spans of the reconciliation and the applied objects", func() {
+		Expect(getSpans(recorder, "Reconcile", attribute.String("sample.name", sampleCR.Name),
+			attribute.String("result", "success"))).NotTo(BeEmpty())
+		Expect(getSpans(recorder, "HandleProcessingState", attribute.String("sample.name", sampleCR.Name),
+			attribute.String("sample.state", string(v1alpha1.StateProcessing)))).NotTo(BeEmpty())
+		Expect(getSpans(recorder, "loadManifestResources", attribute.String("sample.name", sampleCR.Name),
+			attribute.String("manifest.source", "resourceFilePath"), attribute.Int("manifest.objects", 3))).
+			NotTo(BeEmpty())
+
+		applies := getSpans(recorder, "Apply", attribute.String("k8s.kind", "Pod"),
+			attribute.String("k8s.name", podName))
+		Expect(applies).NotTo(BeEmpty())
+		Expect(applies[0].Parent().IsValid()).To(BeTrue())
+		Expect(getSpans(recorder, "ApplyStatus", attribute.String("k8s.kind", string(v1alpha1.SampleKind)),
+			attribute.String("k8s.name", sampleCR.Name))).NotTo(BeEmpty())
 	})
 
 	It("should set state to Warning when deleted after setting FinalDeletionState", func() {
-		reconciler.FinalDeletionState = v1alpha1.StateWarning
+		Expect(k8sClient.Get(ctx, sampleCRKey, sampleCR)).To(Succeed())
+		sampleCR.Spec.FinalDeletionState = v1alpha1.StateWarning
+		Expect(k8sClient.Update(ctx, sampleCR)).To(Succeed())
 		Expect(k8sClient.Delete(ctx, sampleCR)).To(Succeed())
 
 		Eventually(getCRStatus(sampleCRKey)).
@@ -60,26 +102,61 @@ var _ = Describe("Sample CR is created with the correct resource path", Ordered,
 	})
 
 	It("should delete when FinalDeletionState set to Deleting", func() {
-		reconciler.FinalDeletionState = v1alpha1.StateDeleting
+		Expect(k8sClient.Get(ctx, sampleCRKey, sampleCR)).To(Succeed())
+		sampleCR.Spec.FinalDeletionState = v1alpha1.StateDeleting
+		Expect(k8sClient.Update(ctx, sampleCR)).To(Succeed())
 		Eventually(checkDeleted(sampleCRKey)).
 			WithTimeout(30 * time.Second).
 			WithPolling(500 * time.Millisecond).
 			Should(BeTrue())
+
+		Expect(getSpans(recorder, "HandleDeletingState", attribute.String("sample.name", sampleCR.Name))).
+			NotTo(BeEmpty())
+		Expect(getSpans(recorder, "Delete", attribute.String("k8s.kind", "Pod"),
+			attribute.String("k8s.name", podName), attribute.String("result", "success"))).
+			NotTo(BeEmpty())
 	})
 })
 
-var _ = Describe("Sample CR is created with an incorrect resource path", Ordered, func() {
-	sampleCR := createSampleCR("invalid-sample", "./invalid/path")
+var _ = Describe("Sample CR is created with a FinalState", Ordered, func() {
+	sampleCR := createSampleCR("final-state-sample", "")
+	sampleCR.Spec.FinalState = v1alpha1.StateWarning
 	sampleCRKey := client.ObjectKeyFromObject(sampleCR)
 
-	It("should create SampleCR in Error state", func() {
+	BeforeAll(func() {
+		sampleCR.Spec.ResourceFilePath = createConfigMapManifest("final-state-config")
+	})
+
+	It("should set state to FinalState once the resources are healthy", func() {
 		Expect(k8sClient.Create(ctx, sampleCR)).To(Succeed())
+
 		Eventually(getCRStatus(sampleCRKey)).
 			WithTimeout(30 * time.Second).
 			WithPolling(500 * time.Millisecond).
-			Should(Equal(CRStatus{State: v1alpha1.StateError, InstallConditionStatus: metav1.ConditionFalse, Err: nil}))
+			Should(Equal(CRStatus{
+				State:                  v1alpha1.StateWarning,
+				InstallConditionStatus: metav1.ConditionTrue, Err: nil,
+			}))
+	})
 
+	It("should delete the SampleCR", func() {
 		Expect(k8sClient.Delete(ctx, sampleCR)).To(Succeed())
+		Eventually(func() bool {
+			return errors.IsNotFound(k8sClient.Get(ctx, sampleCRKey, &v1alpha1.Sample{}))
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeTrue())
+	})
+})
+
+var _ = Describe("Sample CR is created with an incorrect resource path", Ordered, func() {
+	sampleCR := createSampleCR("invalid-sample", "./invalid/path")
+
+	It("should reject the SampleCR", func() {
+		err := k8sClient.Create(ctx, sampleCR)
+		Expect(errors.IsInvalid(err)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("spec.resourceFilePath")))
 	})
 })
 
@@ -160,3 +237,57 @@ func checkDeleted(sampleObjKey client.ObjectKey) func(g Gomega) bool {
 		return false
 	}
 }
+
+// getMetricValue returns the value of the gauge or counter with the labels, or the number of observations
+// of the histogram with the labels, from the metrics registry of controller-runtime.
+func getMetricValue(name string, labels map[string]string) func(g Gomega) float64 {
+	return func(gomega Gomega) float64 {
+		families, err := metrics.Registry.Gather()
+		gomega.Expect(err).NotTo(HaveOccurred())
+		for _, family := range families {
+			if family.GetName() != name {
+				continue
+			}
+			for _, metric := range family.GetMetric() {
+				if hasLabels(metric, labels) {
+					return metric.GetGauge().GetValue() + metric.GetCounter().GetValue() +
+						float64(metric.GetHistogram().GetSampleCount())
+				}
+			}
+		}
+		return 0
+	}
+}
+
+func hasLabels(metric *dto.Metric, labels map[string]string) bool {
+	matched := 0
+	for _, label := range metric.GetLabel() {
+		if value, found := labels[label.GetName()]; found && value == label.GetValue() {
+			matched++
+		}
+	}
+	return matched == len(labels)
+}
+
+// getSpans returns the ended spans with the name which have all the attributes.
+func getSpans(recorder *tracetest.SpanRecorder, name string, attrs ...attribute.KeyValue) []sdktrace.ReadOnlySpan {
+	spans := make([]sdktrace.ReadOnlySpan, 0)
+	for _, span := range recorder.Ended() {
+		if span.Name() == name && hasAttributes(span, attrs) {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+func hasAttributes(span sdktrace.ReadOnlySpan, attrs []attribute.KeyValue) bool {
+	matched := 0
+	for _, attr := range span.Attributes() {
+		for _, expected := range attrs {
+			if attr == expected {
+				matched++
+			}
+		}
+	}
+	return matched == len(attrs)
+}
@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	errors2 "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+)
+
+// SampleWebhook validates and defaults Samples on admission, so malformed specs are rejected
+// instead of ending up in Error state during reconciliation.
+type SampleWebhook struct {
+	// DefaultResourceFilePath is set as ResourceFilePath of Samples without any manifest source
+	DefaultResourceFilePath string
+}
+
+// +kubebuilder:webhook:path=/mutate-operator-kyma-project-io-v1alpha1-sample,mutating=true,failurePolicy=fail,sideEffects=None,groups=operator.kyma-project.io,resources=samples,verbs=create;update,versions=v1alpha1,name=msample.kb.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-operator-kyma-project-io-v1alpha1-sample,mutating=false,failurePolicy=fail,sideEffects=None,groups=operator.kyma-project.io,resources=samples,verbs=create;update,versions=v1alpha1,name=vsample.kb.io,admissionReviewVersions=v1
+
+// SetupWebhookWithManager registers the defaulting, validating and conversion webhooks of Samples
+// at the webhook server of the Manager.
+func (w *SampleWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	if err := ctrl.NewWebhookManagedBy(mgr, &v1alpha1.Sample{}).
+		WithDefaulter(w).
+		WithValidator(w).
+		Complete(); err != nil {
+		return fmt.Errorf("error while setting up webhook: %w", err)
+	}
+	return nil
+}
+
+// Default sets the defaults of the Sample which are not covered by the defaults of the CRD.
+func (w *SampleWebhook) Default(_ context.Context, sample *v1alpha1.Sample) error {
+	spec := &sample.Spec
+	if getSourceKind(spec) == "" && w.DefaultResourceFilePath != "" {
+		spec.ResourceFilePath = w.DefaultResourceFilePath
+	}
+	if spec.DriftPolicy == "" {
+		spec.DriftPolicy = v1alpha1.DriftPolicyRemediate
+	}
+	if spec.DeletionPolicy == "" {
+		spec.DeletionPolicy = v1alpha1.DeletionPolicyDelete
+	}
+	if spec.ManifestParsing == "" {
+		spec.ManifestParsing = v1alpha1.ManifestParsingStrict
+	}
+	return nil
+}
+
+// ValidateCreate rejects a Sample with a malformed spec, or a name which can not label its resources.
+func (w *SampleWebhook) ValidateCreate(_ context.Context, sample *v1alpha1.Sample) (admission.Warnings, error) {
+	errs := validateSampleSpec(&sample.Spec, field.NewPath("spec"))
+	// the name is set as the value of the sample.kyma-project.io/name label of all applied resources
+	for _, message := range validation.IsValidLabelValue(sample.GetName()) {
+		errs = append(errs, field.Invalid(field.NewPath("metadata", "name"), sample.GetName(), message))
+	}
+	return nil, toInvalidError(sample, errs)
+}
+
+// ValidateUpdate rejects a changed spec which is malformed or changes the kind of the manifest source.
+// An unchanged spec is not validated again, so a Sample whose manifest disappeared can still be updated,
+// e.g. to remove its finalizer, to suspend it or to release its deletion.
+func (w *SampleWebhook) ValidateUpdate(_ context.Context,
+	oldSample, sample *v1alpha1.Sample,
+) (admission.Warnings, error) {
+	// suspending is always admitted, so resources can be hot-fixed even if the manifest is broken,
+	// and so is changing the final deletion state, so a stuck deletion can be released
+	oldSpec := *oldSample.Spec.DeepCopy()
+	oldSpec.Suspend = sample.Spec.Suspend
+	oldSpec.FinalDeletionState = sample.Spec.FinalDeletionState
+	if equality.Semantic.DeepEqual(oldSpec, sample.Spec) {
+		return nil, nil
+	}
+	specPath := field.NewPath("spec")
+	errs := validateSampleSpec(&sample.Spec, specPath)
+	if oldKind, kind := getSourceKind(&oldSample.Spec), getSourceKind(&sample.Spec); kind != "" && oldKind != kind {
+		errs = append(errs, field.Forbidden(specPath.Child(kind),
+			fmt.Sprintf("the manifest source can not be changed from %s to %s, create a new Sample instead",
+				oldKind, kind)))
+	}
+	return nil, toInvalidError(sample, errs)
+}
+
+// ValidateDelete allows every deletion, the resources of the Sample are removed by its finalizer.
+func (w *SampleWebhook) ValidateDelete(_ context.Context, _ *v1alpha1.Sample) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// getSourceKind returns the JSON name of the manifest source configured in the spec, or empty if none is set.
+// If several are set, the one taking precedence in loadManifestResources is returned.
+func getSourceKind(spec *v1alpha1.SampleSpec) string {
+	switch {
+	case spec.Helm != nil:
+		return "helm"
+	case spec.Kustomize != nil:
+		return "kustomize"
+	case spec.ResourceFilePath != "":
+		return "resourceFilePath"
+	default:
+		return ""
+	}
+}
+
+// validateSampleSpec checks that exactly one manifest source is set, that its local paths exist
+// and that the file patterns, health checks and scenario steps are well-formed.
+func validateSampleSpec(spec *v1alpha1.SampleSpec, specPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	sources := 0
+	if spec.ResourceFilePath != "" {
+		sources++
+		errs = append(errs, validateLocalPath(spec.ResourceFilePath, specPath.Child("resourceFilePath"))...)
+	}
+	if spec.Helm != nil {
+		sources++
+		helmPath := specPath.Child("helm")
+		errs = append(errs, validateLocalPath(spec.Helm.ChartPath, helmPath.Child("chartPath"))...)
+		for i, valuesFile := range spec.Helm.ValuesFiles {
+			errs = append(errs, validateLocalPath(valuesFile, helmPath.Child("valuesFiles").Index(i))...)
+		}
+	}
+	if spec.Kustomize != nil {
+		sources++
+		errs = append(errs, validateLocalPath(spec.Kustomize.Path, specPath.Child("kustomize", "path"))...)
+	}
+	switch {
+	case sources == 0:
+		errs = append(errs, field.Required(specPath.Child("resourceFilePath"),
+			"exactly one of resourceFilePath, helm and kustomize must be set"))
+	case sources > 1:
+		errs = append(errs, field.Invalid(specPath, getSourceKind(spec),
+			"exactly one of resourceFilePath, helm and kustomize must be set"))
+	}
+
+	errs = append(errs, validateFilePatterns(spec.IncludePatterns, specPath.Child("includePatterns"))...)
+	errs = append(errs, validateFilePatterns(spec.ExcludePatterns, specPath.Child("excludePatterns"))...)
+	if spec.ResourceFilePath == "" && len(spec.IncludePatterns)+len(spec.ExcludePatterns) > 0 {
+		errs = append(errs, field.Forbidden(specPath.Child("includePatterns"),
+			"file patterns can only be set together with resourceFilePath"))
+	}
+
+	checkedKinds := make(map[schema.GroupKind]bool, len(spec.HealthChecks))
+	for i, check := range spec.HealthChecks {
+		groupKind := schema.GroupKind{Group: check.Group, Kind: check.Kind}
+		if checkedKinds[groupKind] {
+			errs = append(errs, field.Duplicate(specPath.Child("healthChecks").Index(i), groupKind.String()))
+		}
+		checkedKinds[groupKind] = true
+	}
+
+	if scenario := spec.Scenario; scenario != nil {
+		scenarioPath := specPath.Child("scenario")
+		switch {
+		case scenario.ConfigMapName == "":
+			errs = append(errs, validateScenarioSteps(scenario.Steps, scenarioPath.Child("steps"))...)
+		case len(scenario.Steps) > 0:
+			errs = append(errs, field.Invalid(scenarioPath, scenario.ConfigMapName,
+				"exactly one of steps and configMapName must be set"))
+		}
+	}
+	return errs
+}
+
+// validateLocalPath checks that the path exists in the file system of the operator.
+func validateLocalPath(localPath string, fldPath *field.Path) field.ErrorList {
+	if localPath == "" {
+		return field.ErrorList{field.Required(fldPath, "path must not be empty")}
+	}
+	if _, err := os.Stat(localPath); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, localPath, "path does not exist in the operator: "+err.Error())}
+	}
+	return nil
+}
+
+func validateFilePatterns(patterns []string, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	for i, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			errs = append(errs, field.Invalid(fldPath.Index(i), pattern, err.Error()))
+		}
+	}
+	return errs
+}
+
+func toInvalidError(sample *v1alpha1.Sample, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors2.NewInvalid(v1alpha1.GroupVersion.WithKind(string(v1alpha1.SampleKind)).GroupKind(),
+		sample.GetName(), errs)
+}
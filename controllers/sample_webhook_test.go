@@ -0,0 +1,147 @@
+package controllers_test
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+	"github.com/kyma-project/template-operator/api/v1beta1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sample CR is created without a manifest source", Ordered, func() {
+	sampleCR := createSampleCR("webhook-default-sample", "")
+	sampleCRKey := client.ObjectKeyFromObject(sampleCR)
+
+	It("should default the spec and install the default manifest", func() {
+		Expect(k8sClient.Create(ctx, sampleCR)).To(Succeed())
+
+		Expect(sampleCR.Spec.ResourceFilePath).To(Equal(defaultResourceFilePath))
+		Expect(sampleCR.Spec.DriftPolicy).To(Equal(v1alpha1.DriftPolicyRemediate))
+		Expect(sampleCR.Spec.DeletionPolicy).To(Equal(v1alpha1.DeletionPolicyDelete))
+		Expect(sampleCR.Spec.ManifestParsing).To(Equal(v1alpha1.ManifestParsingStrict))
+		Eventually(getCRStatus(sampleCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Equal(CRStatus{State: v1alpha1.StateReady, InstallConditionStatus: metav1.ConditionTrue, Err: nil}))
+	})
+
+	It("should reject a name which is too long for a label value", func() {
+		longSampleCR := createSampleCR(strings.Repeat("a", 64), "")
+
+		err := k8sClient.Create(ctx, longSampleCR)
+		Expect(errors.IsInvalid(err)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("metadata.name")))
+	})
+
+	It("should reject changing the kind of the manifest source", func() {
+		Expect(k8sClient.Get(ctx, sampleCRKey, sampleCR)).To(Succeed())
+		sampleCR.Spec.ResourceFilePath = ""
+		sampleCR.Spec.Kustomize = &v1alpha1.KustomizeSource{Path: "./test/kustomize"}
+
+		err := k8sClient.Update(ctx, sampleCR)
+		Expect(errors.IsInvalid(err)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("spec.kustomize")))
+	})
+
+	It("should admit an unchanged spec even if the manifest no longer exists", func() {
+		Expect(k8sClient.Get(ctx, sampleCRKey, sampleCR)).To(Succeed())
+		sampleCR.Spec.ResourceFilePath = createTempManifestPath("webhook-removed")
+		Expect(k8sClient.Update(ctx, sampleCR)).To(Succeed())
+		Expect(os.RemoveAll(sampleCR.Spec.ResourceFilePath)).To(Succeed())
+
+		Expect(k8sClient.Get(ctx, sampleCRKey, sampleCR)).To(Succeed())
+		sampleCR.SetLabels(map[string]string{"webhook-test": "updated"})
+		Expect(k8sClient.Update(ctx, sampleCR)).To(Succeed())
+	})
+
+	It("should delete the SampleCR", func() {
+		Expect(k8sClient.Delete(ctx, sampleCR)).To(Succeed())
+		Eventually(func() bool {
+			return errors.IsNotFound(k8sClient.Get(ctx, sampleCRKey, &v1alpha1.Sample{}))
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeTrue())
+	})
+})
+
+var _ = Describe("Sample CR is created with a malformed spec", func() {
+	DescribeTable("should reject the SampleCR",
+		func(spec v1alpha1.SampleSpec, field string) {
+			sampleCR := createSampleCR("webhook-invalid-sample", "")
+			sampleCR.Spec = spec
+
+			err := k8sClient.Create(ctx, sampleCR)
+			Expect(errors.IsInvalid(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring(field)))
+		},
+		Entry("with a nonexistent resource file path",
+			v1alpha1.SampleSpec{ResourceFilePath: "./test/does-not-exist"}, "spec.resourceFilePath"),
+		Entry("with several manifest sources",
+			v1alpha1.SampleSpec{
+				ResourceFilePath: "./test/webhook",
+				Kustomize:        &v1alpha1.KustomizeSource{Path: "./test/kustomize"},
+			}, "exactly one of resourceFilePath, helm and kustomize"),
+		Entry("with a nonexistent helm chart",
+			v1alpha1.SampleSpec{Helm: &v1alpha1.HelmSource{ChartPath: "./test/does-not-exist"}},
+			"spec.helm.chartPath"),
+		Entry("with a malformed include pattern",
+			v1alpha1.SampleSpec{ResourceFilePath: "./test/webhook", IncludePatterns: []string{"[a-"}},
+			"spec.includePatterns[0]"),
+		Entry("with file patterns for a kustomization",
+			v1alpha1.SampleSpec{
+				Kustomize:       &v1alpha1.KustomizeSource{Path: "./test/kustomize"},
+				ExcludePatterns: []string{"*.yml"},
+			}, "spec.includePatterns"),
+		Entry("with several health checks of a kind",
+			v1alpha1.SampleSpec{
+				ResourceFilePath: "./test/webhook",
+				HealthChecks: []v1alpha1.CustomHealthCheck{
+					{Group: "example.com", Kind: "Widget", ConditionType: "Ready"},
+					{Group: "example.com", Kind: "Widget", ConditionType: "Available"},
+				},
+			}, "spec.healthChecks[1]"),
+	)
+})
+
+var _ = Describe("Sample CR is created in version v1beta1", Ordered, func() {
+	sampleCR := &v1beta1.Sample{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhook-v1beta1-sample", Namespace: metav1.NamespaceDefault},
+		Spec: v1beta1.SampleSpec{Source: v1beta1.ManifestSource{Path: &v1beta1.PathSource{
+			Path:            "./test/webhook",
+			IncludePatterns: []string{"*.yaml"},
+		}}},
+	}
+	sampleCRKey := client.ObjectKeyFromObject(sampleCR)
+
+	It("should be converted to and validated as v1alpha1", func() {
+		Expect(k8sClient.Create(ctx, sampleCR)).To(Succeed())
+
+		hubSampleCR := &v1alpha1.Sample{}
+		Expect(k8sClient.Get(ctx, sampleCRKey, hubSampleCR)).To(Succeed())
+		Expect(hubSampleCR.Spec.ResourceFilePath).To(Equal("./test/webhook"))
+		Expect(hubSampleCR.Spec.IncludePatterns).To(ConsistOf("*.yaml"))
+
+		Expect(k8sClient.Get(ctx, sampleCRKey, sampleCR)).To(Succeed())
+		sampleCR.Spec.Source.Path.Path = "./test/does-not-exist"
+		Expect(errors.IsInvalid(k8sClient.Update(ctx, sampleCR))).To(BeTrue())
+	})
+
+	It("should delete the SampleCR", func() {
+		Expect(k8sClient.Delete(ctx, sampleCR)).To(Succeed())
+		Eventually(func() bool {
+			return errors.IsNotFound(k8sClient.Get(ctx, sampleCRKey, &v1alpha1.Sample{}))
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeTrue())
+	})
+})
@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+)
+
+// scenarioStepsKey is the key of the ConfigMap referenced by a Scenario holding its steps.
+const scenarioStepsKey = "steps"
+
+var (
+	errScenarioConfigMap = errors.New("invalid scenario ConfigMap")
+
+	//nolint:gochecknoglobals // the States a scenario step can report, Deleting is determined by the deletion
+	scenarioStates = sets.New(v1alpha1.StateProcessing, v1alpha1.StateReady, v1alpha1.StateError,
+		v1alpha1.StateWarning)
+)
+
+// HandleScenarioState reports the State of the current step of the Scenario of the Sample, while its resources
+// are processed as usual and reflected in its conditions. Reconciliations are spaced by the requeueInterval,
+// so that the reconciliations triggered by the status updates are not counted as steps.
+// It returns the interval after which the Sample is reconciled again.
+func (r *SampleReconciler) HandleScenarioState(ctx context.Context, objectInstance *v1alpha1.Sample,
+) (time.Duration, error) {
+	status := getStatusFromSample(objectInstance)
+	generation := objectInstance.GetGeneration()
+	if objectInstance.Spec.Scenario == nil {
+		// the state is determined by the health of the resources again
+		status.Scenario = nil
+		meta.RemoveStatusCondition(&status.Conditions, v1alpha1.ConditionTypeScenario)
+		return requeueInterval, r.setStatusForObjectInstance(ctx, objectInstance,
+			status.WithState(v1alpha1.StateProcessing))
+	}
+
+	steps, err := r.getScenarioSteps(ctx, objectInstance)
+	if err != nil {
+		status.WithState(v1alpha1.StateError).WithCondition(v1alpha1.ConditionTypeScenario, metav1.ConditionFalse,
+			v1alpha1.ConditionReasonScenarioInvalid, err.Error(), generation)
+		return 0, errors.Join(err, r.setStatusIfChanged(ctx, objectInstance, &status))
+	}
+
+	now := metav1.Now()
+	progress := status.Scenario
+	if progress == nil || progress.ObservedGeneration != generation {
+		progress = &v1alpha1.ScenarioStatus{StepStartTime: now, ObservedGeneration: generation}
+	} else if elapsed := now.Sub(progress.LastReconcileTime.Time); elapsed < requeueInterval {
+		return requeueInterval - elapsed, nil
+	}
+	if advanceScenario(progress, steps, now) {
+		r.Eventf(objectInstance, nil, "Normal", "ScenarioStep", "Scenario", "%s",
+			getScenarioStepMessage(steps, progress.Step))
+	}
+	progress.Reconciles++
+	progress.LastReconcileTime = now
+	step := steps[progress.Step]
+
+	if err := r.processResources(ctx, objectInstance, &status); err != nil {
+		setFailedConditions(&status, getConditionReason(err), err.Error(), generation)
+	} else if r.isDryRun(objectInstance) {
+		setDryRunConditions(&status, generation)
+	} else {
+		r.setConditionsFromHealth(&status, r.getFinalState(objectInstance), generation)
+	}
+	reason := v1alpha1.ConditionReasonScenarioRunning
+	if int(progress.Step) == len(steps)-1 {
+		reason = v1alpha1.ConditionReasonScenarioCompleted
+	}
+	status.Scenario = progress
+	status.WithState(step.State).WithCondition(v1alpha1.ConditionTypeScenario, metav1.ConditionTrue, reason,
+		getScenarioStepMessage(steps, progress.Step), generation)
+	return r.getScenarioRequeueInterval(objectInstance, progress, steps), r.setStatusIfChanged(ctx, objectInstance,
+		&status)
+}
+
+// advanceScenario moves the progress to the next step once the current step is done,
+// and reports whether a step started. The last step is kept once it is reached.
+func advanceScenario(progress *v1alpha1.ScenarioStatus, steps []v1alpha1.ScenarioStep, now metav1.Time) bool {
+	last := int32(len(steps) - 1) //nolint:gosec // the steps are part of a single object
+	switch {
+	case progress.Step > last:
+		// the steps of a ConfigMap can be shortened while they run
+		progress.Step = last
+	case progress.Step == last || !isScenarioStepDone(steps[progress.Step], progress, now):
+		return progress.Reconciles == 0
+	default:
+		progress.Step++
+	}
+	progress.StepStartTime = now
+	progress.Reconciles = 0
+	return true
+}
+
+// isScenarioStepDone reports whether the Duration of the step elapsed or the Sample was reconciled
+// the number of its Reconciles.
+func isScenarioStepDone(step v1alpha1.ScenarioStep, progress *v1alpha1.ScenarioStatus, now metav1.Time) bool {
+	if step.Duration != nil && now.Sub(progress.StepStartTime.Time) >= step.Duration.Duration {
+		return true
+	}
+	return step.Reconciles > 0 && progress.Reconciles >= step.Reconciles
+}
+
+// getScenarioRequeueInterval returns the interval after which the current step of the Scenario is checked again.
+func (r *SampleReconciler) getScenarioRequeueInterval(objectInstance *v1alpha1.Sample,
+	progress *v1alpha1.ScenarioStatus, steps []v1alpha1.ScenarioStep,
+) time.Duration {
+	step := steps[progress.Step]
+	if int(progress.Step) == len(steps)-1 {
+		if step.State == v1alpha1.StateReady || step.State == v1alpha1.StateWarning {
+			return r.getReadyRequeueInterval(objectInstance)
+		}
+		return requeueInterval
+	}
+	if step.Reconciles > 0 || step.Duration == nil {
+		return requeueInterval
+	}
+	return max(step.Duration.Duration-time.Since(progress.StepStartTime.Time), requeueInterval)
+}
+
+// getScenarioSteps returns the steps of the Scenario of the Sample, which are either part of the spec
+// or read from the referenced ConfigMap.
+func (r *SampleReconciler) getScenarioSteps(ctx context.Context, objectInstance *v1alpha1.Sample,
+) ([]v1alpha1.ScenarioStep, error) {
+	scenario := objectInstance.Spec.Scenario
+	if scenario.ConfigMapName == "" {
+		return scenario.Steps, nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	key := client.ObjectKey{Namespace: objectInstance.GetNamespace(), Name: scenario.ConfigMapName}
+	if err := r.Get(ctx, key, configMap); err != nil {
+		return nil, fmt.Errorf("error while getting scenario ConfigMap %s: %w", key, err)
+	}
+	var steps []v1alpha1.ScenarioStep
+	if err := yaml.UnmarshalStrict([]byte(configMap.Data[scenarioStepsKey]), &steps); err != nil {
+		return nil, fmt.Errorf("%w %s: %w", errScenarioConfigMap, key, err)
+	}
+	if errs := validateScenarioSteps(steps, field.NewPath("data", scenarioStepsKey)); len(errs) > 0 {
+		return nil, fmt.Errorf("%w %s: %w", errScenarioConfigMap, key, errs.ToAggregate())
+	}
+	return steps, nil
+}
+
+// validateScenarioSteps checks that there is at least one step, that the steps report valid States
+// and that only the last step lasts forever.
+func validateScenarioSteps(steps []v1alpha1.ScenarioStep, fldPath *field.Path) field.ErrorList {
+	if len(steps) == 0 {
+		return field.ErrorList{field.Required(fldPath, "at least one step must be set")}
+	}
+	var errs field.ErrorList
+	for i, step := range steps {
+		stepPath := fldPath.Index(i)
+		if !scenarioStates.Has(step.State) {
+			errs = append(errs, field.NotSupported(stepPath.Child("state"), step.State,
+				sets.List(scenarioStates)))
+		}
+		if step.Duration != nil && step.Duration.Duration <= 0 {
+			errs = append(errs, field.Invalid(stepPath.Child("duration"), step.Duration.String(),
+				"duration must be positive"))
+		}
+		if step.Reconciles < 0 {
+			errs = append(errs, field.Invalid(stepPath.Child("reconciles"), step.Reconciles,
+				"reconciles must not be negative"))
+		}
+		if i < len(steps)-1 && step.Duration == nil && step.Reconciles == 0 {
+			errs = append(errs, field.Required(stepPath.Child("duration"),
+				"only the last step can be set without duration or reconciles"))
+		}
+	}
+	return errs
+}
+
+// getScenarioStepMessage describes the step of the Scenario, e.g. "step 2 of 4: Error for 2 reconciles".
+func getScenarioStepMessage(steps []v1alpha1.ScenarioStep, index int32) string {
+	step := steps[index]
+	message := fmt.Sprintf("step %d of %d: %s", index+1, len(steps), step.State)
+	switch {
+	case step.Duration != nil && step.Reconciles > 0:
+		message += fmt.Sprintf(" for %v or %d reconciles", step.Duration.Duration, step.Reconciles)
+	case step.Duration != nil:
+		message += fmt.Sprintf(" for %v", step.Duration.Duration)
+	case step.Reconciles > 0:
+		message += fmt.Sprintf(" for %d reconciles", step.Reconciles)
+	}
+	if step.Message != "" {
+		message += ": " + step.Message
+	}
+	return message
+}
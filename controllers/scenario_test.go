@@ -0,0 +1,130 @@
+package controllers_test
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sample CR is created with a scenario", Ordered, func() {
+	sampleCR := createSampleCR("scenario-sample", "")
+	sampleCR.Spec.Scenario = &v1alpha1.Scenario{Steps: []v1alpha1.ScenarioStep{
+		{State: v1alpha1.StateError, Reconciles: 2},
+		{State: v1alpha1.StateWarning, Duration: &metav1.Duration{Duration: 5 * time.Second}},
+		{State: v1alpha1.StateReady, Message: "scenario done"},
+	}}
+	sampleCRKey := client.ObjectKeyFromObject(sampleCR)
+	configKey := client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "scenario-config"}
+
+	BeforeAll(func() {
+		sampleCR.Spec.ResourceFilePath = createConfigMapManifest(configKey.Name)
+	})
+
+	It("should report the states of the steps in order", func() {
+		Expect(k8sClient.Create(ctx, sampleCR)).To(Succeed())
+
+		for _, state := range []v1alpha1.State{v1alpha1.StateError, v1alpha1.StateWarning, v1alpha1.StateReady} {
+			Eventually(getCRStatus(sampleCRKey)).
+				WithTimeout(30 * time.Second).
+				WithPolling(500 * time.Millisecond).
+				Should(HaveField("State", state))
+		}
+		Eventually(getCondition(sampleCRKey, v1alpha1.ConditionTypeScenario)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(And(
+				HaveField("Reason", v1alpha1.ConditionReasonScenarioCompleted),
+				HaveField("Message", Equal("step 3 of 3: Ready: scenario done")),
+			))
+	})
+
+	It("should still apply the resources", func() {
+		Expect(k8sClient.Get(ctx, configKey, &v1.ConfigMap{})).To(Succeed())
+	})
+
+	It("should report the state of the resources once the scenario is removed", func() {
+		Expect(k8sClient.Get(ctx, sampleCRKey, sampleCR)).To(Succeed())
+		sampleCR.Spec.Scenario = nil
+		Expect(k8sClient.Update(ctx, sampleCR)).To(Succeed())
+
+		Eventually(func(g Gomega) *v1alpha1.ScenarioStatus {
+			g.Expect(k8sClient.Get(ctx, sampleCRKey, sampleCR)).To(Succeed())
+			return sampleCR.Status.Scenario
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeNil())
+		Eventually(getCRStatus(sampleCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Equal(CRStatus{State: v1alpha1.StateReady, InstallConditionStatus: metav1.ConditionTrue, Err: nil}))
+	})
+
+	It("should delete the SampleCR", func() {
+		Expect(k8sClient.Delete(ctx, sampleCR)).To(Succeed())
+		Eventually(func() bool {
+			return errors.IsNotFound(k8sClient.Get(ctx, sampleCRKey, &v1alpha1.Sample{}))
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeTrue())
+	})
+})
+
+var _ = Describe("Sample CR is created with a scenario of a ConfigMap", Ordered, func() {
+	scenarioConfigMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "scenario-steps", Namespace: metav1.NamespaceDefault},
+		Data:       map[string]string{"steps": "- state: Warning\n  message: from ConfigMap\n"},
+	}
+	sampleCR := createSampleCR("scenario-configmap-sample", "")
+	sampleCR.Spec.Scenario = &v1alpha1.Scenario{ConfigMapName: scenarioConfigMap.GetName()}
+	sampleCRKey := client.ObjectKeyFromObject(sampleCR)
+
+	BeforeAll(func() {
+		sampleCR.Spec.ResourceFilePath = createConfigMapManifest("scenario-configmap-config")
+	})
+
+	It("should report the state of the steps of the ConfigMap", func() {
+		Expect(k8sClient.Create(ctx, scenarioConfigMap)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, scenarioConfigMap)
+		Expect(k8sClient.Create(ctx, sampleCR)).To(Succeed())
+
+		Eventually(getCondition(sampleCRKey, v1alpha1.ConditionTypeScenario)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(HaveField("Message", ContainSubstring("from ConfigMap")))
+		Expect(getCRStatus(sampleCRKey)(Default)).To(HaveField("State", v1alpha1.StateWarning))
+	})
+
+	It("should report invalid steps of the ConfigMap", func() {
+		scenarioConfigMap.Data["steps"] = "- state: Deleting\n"
+		Expect(k8sClient.Update(ctx, scenarioConfigMap)).To(Succeed())
+
+		Eventually(getCondition(sampleCRKey, v1alpha1.ConditionTypeScenario)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(And(
+				HaveField("Reason", v1alpha1.ConditionReasonScenarioInvalid),
+				HaveField("Message", ContainSubstring("data.steps[0].state")),
+			))
+		Expect(getCRStatus(sampleCRKey)(Default)).To(HaveField("State", v1alpha1.StateError))
+	})
+
+	It("should delete the SampleCR", func() {
+		Expect(k8sClient.Delete(ctx, sampleCR)).To(Succeed())
+		Eventually(func() bool {
+			return errors.IsNotFound(k8sClient.Get(ctx, sampleCRKey, &v1alpha1.Sample{}))
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeTrue())
+	})
+})
@@ -18,10 +18,16 @@ package controllers_test
 
 import (
 	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -29,6 +35,8 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/yaml"
 
 	operatorkymaprojectiov1alpha1 "github.com/kyma-project/template-operator/api/v1alpha1"
 	"github.com/kyma-project/template-operator/controllers"
@@ -54,6 +62,7 @@ const (
 	rateLimiterFrequencyDefault = 30
 	failureBaseDelayDefault     = 1 * time.Second
 	failureMaxDelayDefault      = 1000 * time.Second
+	defaultResourceFilePath     = "./test/webhook"
 )
 
 func TestAPIs(t *testing.T) {
@@ -73,10 +82,20 @@ var _ = BeforeSuite(func() {
 		FailureMaxDelay: failureMaxDelayDefault,
 	}
 
+	// registered before the test environment is started, so the conversion webhook is configured in the CRDs
+	err := controllers.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
+
 	By("bootstrapping test environment")
 	testEnv = &envtest.Environment{
-		CRDDirectoryPaths:     []string{filepath.Join("..", "config", "crd", "bases")},
+		CRDDirectoryPaths: []string{
+			filepath.Join("..", "config", "crd", "bases"),
+			filepath.Join("..", "crd"),
+		},
 		ErrorIfCRDPathMissing: true,
+		WebhookInstallOptions: envtest.WebhookInstallOptions{
+			Paths: []string{filepath.Join("..", "config", "webhook")},
+		},
 	}
 
 	cfg, err := testEnv.Start()
@@ -86,18 +105,21 @@ var _ = BeforeSuite(func() {
 	err = controllers.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
 
-	err = controllers.AddToScheme(scheme.Scheme)
-	Expect(err).NotTo(HaveOccurred())
-
 	//+kubebuilder:scaffold:scheme
 
 	k8sClient, err = client.New(cfg, client.Options{Scheme: scheme.Scheme})
 	Expect(err).NotTo(HaveOccurred())
 	Expect(k8sClient).NotTo(BeNil())
 
+	webhookInstallOptions := &testEnv.WebhookInstallOptions
 	k8sManager, err = ctrl.NewManager(
 		cfg, ctrl.Options{
 			Scheme: scheme.Scheme,
+			WebhookServer: webhook.NewServer(webhook.Options{
+				Host:    webhookInstallOptions.LocalServingHost,
+				Port:    webhookInstallOptions.LocalServingPort,
+				CertDir: webhookInstallOptions.LocalServingCertDir,
+			}),
 		})
 	Expect(err).ToNot(HaveOccurred())
 
@@ -112,11 +134,42 @@ var _ = BeforeSuite(func() {
 	err = reconciler.SetupWithManager(k8sManager, rateLimiter)
 	Expect(err).ToNot(HaveOccurred())
 
+	err = (&controllers.ManagedReconciler{
+		Client:        k8sManager.GetClient(),
+		Scheme:        scheme.Scheme,
+		EventRecorder: k8sManager.GetEventRecorder("tests"),
+	}).SetupWithManager(ctx, k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
+	err = (&controllers.ThirdPartyReconciler{
+		Client:        k8sManager.GetClient(),
+		Scheme:        scheme.Scheme,
+		EventRecorder: k8sManager.GetEventRecorder("tests"),
+	}).SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
+	err = (&controllers.SampleWebhook{
+		DefaultResourceFilePath: defaultResourceFilePath,
+	}).SetupWebhookWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
 	go func() {
 		defer GinkgoRecover()
 		err = k8sManager.Start(ctx)
 		Expect(err).ToNot(HaveOccurred(), "failed to run manager")
 	}()
+
+	By("waiting for the webhook server to serve")
+	dialer := &net.Dialer{Timeout: time.Second}
+	addrPort := fmt.Sprintf("%s:%d", webhookInstallOptions.LocalServingHost, webhookInstallOptions.LocalServingPort)
+	Eventually(func() error {
+		//nolint:gosec // the certificate of the test webhook server is self-signed
+		conn, err := tls.DialWithDialer(dialer, "tcp", addrPort, &tls.Config{InsecureSkipVerify: true})
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}).Should(Succeed())
 })
 
 var _ = AfterSuite(func() {
@@ -126,3 +179,32 @@ var _ = AfterSuite(func() {
 	err := testEnv.Stop()
 	Expect(err).NotTo(HaveOccurred())
 })
+
+// createTempManifestPath creates an empty directory which is admitted as resourceFilePath,
+// so tests can remove it afterwards to simulate a manifest which is no longer available.
+func createTempManifestPath(pattern string) string {
+	dirPath, err := os.MkdirTemp("", pattern)
+	Expect(err).NotTo(HaveOccurred())
+	DeferCleanup(os.RemoveAll, dirPath)
+	return dirPath
+}
+
+// createConfigMapManifest creates a manifest directory with a ConfigMap of the name in the default namespace,
+// so that every test reconciles its own objects.
+func createConfigMapManifest(name string) string {
+	dirPath := createTempManifestPath(name)
+	writeConfigMapManifest(dirPath, name, map[string]string{"key": "value"})
+	return dirPath
+}
+
+// writeConfigMapManifest writes the manifest of a ConfigMap of the name in the default namespace
+// with the data to the manifest directory, replacing the previous one.
+func writeConfigMapManifest(dirPath, name string, data map[string]string) {
+	manifest, err := yaml.Marshal(&v1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: metav1.NamespaceDefault},
+		Data:       data,
+	})
+	Expect(err).NotTo(HaveOccurred())
+	Expect(os.WriteFile(filepath.Join(dirPath, "configmap.yaml"), manifest, 0o600)).To(Succeed())
+}
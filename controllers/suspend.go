@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+)
+
+// pausedAnnotation suspends the reconciliation of a Sample if set to "true", like spec.suspend.
+const pausedAnnotation = "operator.kyma-project.io/paused"
+
+// getSuspendMessage returns why the reconciliation of the Sample is suspended, or empty if it is not.
+func getSuspendMessage(objectInstance *v1alpha1.Sample) string {
+	switch {
+	case objectInstance.Spec.Suspend:
+		return "reconciliation is suspended by spec.suspend"
+	case objectInstance.GetAnnotations()[pausedAnnotation] == "true":
+		return fmt.Sprintf("reconciliation is suspended by the %s annotation", pausedAnnotation)
+	default:
+		return ""
+	}
+}
+
+// isMarkedSuspended reports whether the Suspended condition of the Sample is True.
+func isMarkedSuspended(objectInstance *v1alpha1.Sample) bool {
+	return meta.IsStatusConditionTrue(objectInstance.Status.Conditions, v1alpha1.ConditionTypeSuspended)
+}
+
+// HandleSuspendedState keeps the status of a suspended Sample up to date, without applying, healing, pruning
+// or deleting any of its resources, so that they can be changed manually. The State is kept as is,
+// only the health of the inventory resources and the Suspended condition are updated.
+func (r *SampleReconciler) HandleSuspendedState(ctx context.Context, objectInstance *v1alpha1.Sample,
+	message string,
+) error {
+	status := getStatusFromSample(objectInstance)
+	if !isMarkedSuspended(objectInstance) {
+		r.Eventf(objectInstance, nil, "Normal", "Suspended", "Suspending", "%s", message)
+	}
+	status.WithCondition(v1alpha1.ConditionTypeSuspended, metav1.ConditionTrue, v1alpha1.ConditionReasonSuspended,
+		message, objectInstance.GetGeneration())
+
+	if !r.isDryRun(objectInstance) {
+		resources := make([]*unstructured.Unstructured, 0, len(status.Inventory))
+		for _, entry := range status.Inventory {
+			resources = append(resources, getObjectFromInventoryEntry(entry))
+		}
+		results, err := checkResourcesHealth(ctx, r.Client, resources, objectInstance.Spec.HealthChecks)
+		if err != nil {
+			return err
+		}
+		health, healthMessage := aggregateHealth(results)
+		status.WithHealthCondition(health, healthMessage, objectInstance.GetGeneration())
+	}
+	return r.setStatusIfChanged(ctx, objectInstance, &status)
+}
+
+// HandleResumedState marks a Sample whose reconciliation was suspended as resumed,
+// so that its resources are applied again with the next reconciliation.
+func (r *SampleReconciler) HandleResumedState(ctx context.Context, objectInstance *v1alpha1.Sample) error {
+	r.Eventf(objectInstance, nil, "Normal", "Resumed", "Resuming", "reconciliation is resumed")
+	status := getStatusFromSample(objectInstance)
+	return r.setStatusForObjectInstance(ctx, objectInstance, status.WithCondition(v1alpha1.ConditionTypeSuspended,
+		metav1.ConditionFalse, v1alpha1.ConditionReasonResumed, "reconciliation is resumed",
+		objectInstance.GetGeneration()))
+}
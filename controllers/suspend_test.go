@@ -0,0 +1,95 @@
+package controllers_test
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sample CR is suspended", Ordered, func() {
+	sampleCR := createSampleCR("suspend-sample", "")
+	sampleCRKey := client.ObjectKeyFromObject(sampleCR)
+	configKey := client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "suspend-config"}
+
+	BeforeAll(func() {
+		sampleCR.Spec.ResourceFilePath = createConfigMapManifest(configKey.Name)
+	})
+
+	It("should report the suspension once the paused annotation is set", func() {
+		Expect(k8sClient.Create(ctx, sampleCR)).To(Succeed())
+		Eventually(getCRStatus(sampleCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Equal(CRStatus{State: v1alpha1.StateReady, InstallConditionStatus: metav1.ConditionTrue, Err: nil}))
+
+		Expect(k8sClient.Get(ctx, sampleCRKey, sampleCR)).To(Succeed())
+		sampleCR.SetAnnotations(map[string]string{"operator.kyma-project.io/paused": "true"})
+		Expect(k8sClient.Update(ctx, sampleCR)).To(Succeed())
+
+		Eventually(getCondition(sampleCRKey, v1alpha1.ConditionTypeSuspended)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(And(
+				HaveField("Status", metav1.ConditionTrue),
+				HaveField("Reason", v1alpha1.ConditionReasonSuspended),
+				HaveField("Message", ContainSubstring("annotation")),
+			))
+		Expect(getCRStatus(sampleCRKey)(Default)).To(HaveField("State", v1alpha1.StateReady))
+	})
+
+	It("should keep manual changes of the resources while suspended", func() {
+		configMap := &v1.ConfigMap{}
+		Expect(k8sClient.Get(ctx, configKey, configMap)).To(Succeed())
+		configMap.Data["key"] = "hot-fixed"
+		Expect(k8sClient.Update(ctx, configMap)).To(Succeed())
+
+		Consistently(func(g Gomega) string {
+			g.Expect(k8sClient.Get(ctx, configKey, configMap)).To(Succeed())
+			return configMap.Data["key"]
+		}).
+			WithTimeout(5 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Equal("hot-fixed"))
+	})
+
+	It("should not delete the resources while suspended by spec.suspend", func() {
+		Expect(k8sClient.Get(ctx, sampleCRKey, sampleCR)).To(Succeed())
+		sampleCR.SetAnnotations(nil)
+		sampleCR.Spec.Suspend = true
+		Expect(k8sClient.Update(ctx, sampleCR)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, sampleCR)).To(Succeed())
+
+		Eventually(getCondition(sampleCRKey, v1alpha1.ConditionTypeSuspended)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(HaveField("Message", ContainSubstring("spec.suspend")))
+		Consistently(func() error {
+			return k8sClient.Get(ctx, configKey, &v1.ConfigMap{})
+		}).
+			WithTimeout(5 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Succeed())
+	})
+
+	It("should delete the resources once resumed", func() {
+		Expect(k8sClient.Get(ctx, sampleCRKey, sampleCR)).To(Succeed())
+		sampleCR.Spec.Suspend = false
+		Expect(k8sClient.Update(ctx, sampleCR)).To(Succeed())
+
+		Eventually(func() bool {
+			return errors.IsNotFound(k8sClient.Get(ctx, configKey, &v1.ConfigMap{})) &&
+				errors.IsNotFound(k8sClient.Get(ctx, sampleCRKey, &v1alpha1.Sample{}))
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeTrue())
+	})
+})
@@ -0,0 +1,332 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+)
+
+// ThirdPartyReconciler reconciles a ThirdParty object.
+type ThirdPartyReconciler struct {
+	client.Client
+	events.EventRecorder
+
+	Scheme *runtime.Scheme
+	// ResyncPeriod after which a Ready ThirdParty is reconciled, in addition to the changes of the selected objects
+	ResyncPeriod time.Duration
+
+	watcher *resourceWatcher
+}
+
+// +kubebuilder:rbac:groups=operator.kyma-project.io,resources=thirdparties,verbs=get;list;watch
+// +kubebuilder:rbac:groups=operator.kyma-project.io,resources=thirdparties/status,verbs=get;update;patch
+
+// SetupWithManager sets up the controller with the Manager.
+// The ThirdParty CRD is installed separately from the operator, so the controller is only set up if it is installed.
+func (r *ThirdPartyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	installed, err := isThirdPartyInstalled(mgr)
+	if err != nil || !installed {
+		return err
+	}
+
+	thirdPartyController, err := ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.ThirdParty{}).
+		Build(r)
+	if err != nil {
+		return fmt.Errorf("error while setting up controller: %w", err)
+	}
+	// the kinds of the selected objects are only known at runtime, so they are watched once they are selected
+	r.watcher = newResourceWatcher(thirdPartyController, mgr.GetCache(), r.newThirdPartyHandler)
+	return nil
+}
+
+// isThirdPartyInstalled reports whether the ThirdParty CRD is installed, which is installed separately
+// from the operator.
+func isThirdPartyInstalled(mgr ctrl.Manager) (bool, error) {
+	gvk := v1alpha1.GroupVersion.WithKind(string(v1alpha1.ThirdPartyKind))
+	if _, err := mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+		if meta.IsNoMatchError(err) {
+			mgr.GetLogger().Info("skipping ThirdParties, as the ThirdParty CRD is not installed")
+			return false, nil
+		}
+		return false, fmt.Errorf("error while checking the ThirdParty CRD: %w", err)
+	}
+	return true, nil
+}
+
+// newThirdPartyHandler maps the events of objects of the kind to the ThirdParties selecting them.
+//
+//nolint:ireturn // handlers are only available through the handler.EventHandler interface
+func (r *ThirdPartyReconciler) newThirdPartyHandler(gvk schema.GroupVersionKind) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+		thirdParties := &v1alpha1.ThirdPartyList{}
+		if err := r.List(ctx, thirdParties, client.InNamespace(obj.GetNamespace())); err != nil {
+			log.FromContext(ctx).Error(err, "error while listing thirdparties")
+			return nil
+		}
+		requests := make([]reconcile.Request, 0)
+		for _, thirdParty := range thirdParties.Items {
+			if thirdParty.Spec.APIVersion != gvk.GroupVersion().String() || thirdParty.Spec.Kind != gvk.Kind {
+				continue
+			}
+			selector, err := metav1.LabelSelectorAsSelector(&thirdParty.Spec.Selector)
+			if err != nil || !selector.Matches(labels.Set(obj.GetLabels())) {
+				continue
+			}
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&thirdParty)})
+		}
+		return requests
+	})
+}
+
+// getSampleRequestsOfThirdParty maps an event of a ThirdParty to a reconciliation of the Sample it references.
+func getSampleRequestsOfThirdParty(_ context.Context, obj client.Object) []reconcile.Request {
+	thirdParty, ok := obj.(*v1alpha1.ThirdParty)
+	if !ok || thirdParty.Spec.SampleRef == nil {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{
+		Namespace: thirdParty.GetNamespace(),
+		Name:      thirdParty.Spec.SampleRef.Name,
+	}}}
+}
+
+// Reconcile is the entry point from the controller-runtime framework.
+// It performs a reconciliation based on the passed ctrl.Request object.
+func (r *ThirdPartyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	thirdParty := &v1alpha1.ThirdParty{}
+	if err := r.Get(ctx, req.NamespacedName, thirdParty); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	// the selected objects are released with the ThirdParty, they are no longer part of the adopted inventory
+	if !thirdParty.GetDeletionTimestamp().IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	status := *thirdParty.Status.DeepCopy()
+	objs, err := r.getSelectedResources(ctx, thirdParty)
+	if err != nil {
+		status.WithState(v1alpha1.StateError).WithCondition(v1alpha1.ConditionTypeAdopted, metav1.ConditionFalse,
+			v1alpha1.ConditionReasonSelectionInvalid, err.Error(), thirdParty.GetGeneration())
+		return ctrl.Result{}, errors.Join(err, updateStatusIfChanged(ctx, r.Client, thirdParty, &thirdParty.Status, &status))
+	}
+
+	resources := make([]v1alpha1.ResourceStatus, 0, len(objs))
+	results := make([]healthResult, 0, len(objs))
+	failed := make([]string, 0)
+	for _, obj := range objs {
+		resource := v1alpha1.ResourceStatus{InventoryEntry: newInventoryEntry(obj)}
+		if thirdParty.Spec.Mode == v1alpha1.AdoptionModeTakeOwnership {
+			if err := r.takeOwnership(ctx, obj); err != nil {
+				resource.LastError = err.Error()
+				failed = append(failed, fmt.Sprintf("%s: %v", describeObject(obj), err))
+			}
+		}
+		health, message := evaluateHealth(obj, nil)
+		resource.AppliedGeneration, resource.Health, resource.Message = obj.GetGeneration(), health, message
+		results = append(results, healthResult{
+			obj: obj, generation: obj.GetGeneration(), health: health, message: message,
+		})
+		resources = append(resources, resource)
+	}
+	status.WithResources(resources)
+	health, message := aggregateHealth(results)
+	status.WithCondition(v1alpha1.ConditionTypeHealthy, getHealthConditionStatus(health), string(health), message,
+		thirdParty.GetGeneration())
+
+	if len(failed) > 0 {
+		r.Eventf(thirdParty, nil, "Warning", "OwnershipTakeover", "Processing", "%s", joinMessages(failed))
+		status.WithState(v1alpha1.StateError).WithCondition(v1alpha1.ConditionTypeAdopted, metav1.ConditionFalse,
+			v1alpha1.ConditionReasonAdoptionFailed, joinMessages(failed), thirdParty.GetGeneration())
+		return ctrl.Result{RequeueAfter: requeueInterval}, updateStatusIfChanged(ctx, r.Client, thirdParty, &thirdParty.Status, &status)
+	}
+
+	setAdoptedCondition(thirdParty, &status, len(objs))
+	status.WithState(getStateFromHealthResult(health))
+	result := ctrl.Result{RequeueAfter: getResyncPeriod(r.ResyncPeriod)}
+	if status.State != v1alpha1.StateReady {
+		result.RequeueAfter = requeueInterval
+	}
+	return result, updateStatusIfChanged(ctx, r.Client, thirdParty, &thirdParty.Status, &status)
+}
+
+// getSelectedResources lists the objects selected by the ThirdParty in its namespace.
+func (r *ThirdPartyReconciler) getSelectedResources(ctx context.Context,
+	thirdParty *v1alpha1.ThirdParty,
+) ([]*unstructured.Unstructured, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&thirdParty.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing selector: %w", err)
+	}
+	gv, err := schema.ParseGroupVersion(thirdParty.Spec.APIVersion)
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing apiVersion: %w", err)
+	}
+
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gv.WithKind(thirdParty.Spec.Kind + "List"))
+	if err := r.List(ctx, list, client.InNamespace(thirdParty.GetNamespace()),
+		client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("error while listing %s: %w", thirdParty.Spec.Kind, err)
+	}
+	// the kind is only watched once it is known to the cluster
+	if err := r.watcher.watch(list.GroupVersionKind().GroupVersion().WithKind(thirdParty.Spec.Kind)); err != nil {
+		return nil, err
+	}
+	objs := make([]*unstructured.Unstructured, 0, len(list.Items))
+	for i := range list.Items {
+		objs = append(objs, &list.Items[i])
+	}
+	return objs, nil
+}
+
+// takeOwnership applies the full object with the field owner of the operator, so that the operator owns all fields,
+// and removes the entries of the previous field managers which only share the fields with equal values.
+func (r *ThirdPartyReconciler) takeOwnership(ctx context.Context, obj *unstructured.Unstructured) error {
+	if len(getReplacedManagedFields(obj)) == 0 {
+		return nil
+	}
+
+	applied := obj.DeepCopy()
+	delete(applied.Object, "status")
+	unstructured.RemoveNestedField(applied.Object, "metadata", "managedFields")
+	unstructured.RemoveNestedField(applied.Object, "metadata", "resourceVersion")
+	unstructured.RemoveNestedField(applied.Object, "metadata", "uid")
+	unstructured.RemoveNestedField(applied.Object, "metadata", "generation")
+	unstructured.RemoveNestedField(applied.Object, "metadata", "creationTimestamp")
+	if err := r.Apply(ctx, client.ApplyConfigurationFromUnstructured(applied),
+		client.ForceOwnership, client.FieldOwner(fieldOwner)); err != nil {
+		return fmt.Errorf("error while applying fields: %w", err)
+	}
+
+	if err := r.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+		return fmt.Errorf("error while getting object: %w", err)
+	}
+	replaced := getReplacedManagedFields(obj)
+	if len(replaced) == 0 {
+		return nil
+	}
+	// the entries are removed back to front so that the indices stay valid, each guarded by its manager
+	patch := make([]map[string]any, 0, 2*len(replaced))
+	for _, index := range slices.Backward(replaced) {
+		path := fmt.Sprintf("/metadata/managedFields/%d", index)
+		patch = append(patch,
+			map[string]any{"op": "test", "path": path + "/manager", "value": obj.GetManagedFields()[index].Manager},
+			map[string]any{"op": "remove", "path": path})
+	}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return fmt.Errorf("error while marshalling patch: %w", err)
+	}
+	if err := r.Patch(ctx, obj, client.RawPatch(types.JSONPatchType, data)); err != nil {
+		return fmt.Errorf("error while removing previous field managers: %w", err)
+	}
+	log.FromContext(ctx).V(debugLogLevel).Info("took ownership of " + describeObject(obj))
+	return nil
+}
+
+// getReplacedManagedFields returns the indices of the managed fields of the previous field managers of the object.
+// The managed fields of subresources like status remain with the controllers of the object.
+func getReplacedManagedFields(obj *unstructured.Unstructured) []int {
+	replaced := make([]int, 0)
+	for index, entry := range obj.GetManagedFields() {
+		if entry.Manager != fieldOwner && entry.Subresource == "" {
+			replaced = append(replaced, index)
+		}
+	}
+	return replaced
+}
+
+// setAdoptedCondition reports whether the selected objects are adopted by the referenced Sample.
+func setAdoptedCondition(thirdParty *v1alpha1.ThirdParty, status *v1alpha1.ThirdPartyStatus, count int) {
+	switch thirdParty.Spec.Mode {
+	case v1alpha1.AdoptionModeAdopt:
+		status.WithCondition(v1alpha1.ConditionTypeAdopted, metav1.ConditionTrue, v1alpha1.ConditionReasonAdopted,
+			fmt.Sprintf("%d objects adopted by sample %s", count, thirdParty.Spec.SampleRef.Name),
+			thirdParty.GetGeneration())
+	case v1alpha1.AdoptionModeTakeOwnership:
+		status.WithCondition(v1alpha1.ConditionTypeAdopted, metav1.ConditionTrue, v1alpha1.ConditionReasonOwnershipTaken,
+			fmt.Sprintf("%d objects adopted and owned by sample %s", count, thirdParty.Spec.SampleRef.Name),
+			thirdParty.GetGeneration())
+	default:
+		status.WithCondition(v1alpha1.ConditionTypeAdopted, metav1.ConditionFalse, v1alpha1.ConditionReasonObserved,
+			fmt.Sprintf("%d objects are observed without being managed", count), thirdParty.GetGeneration())
+	}
+}
+
+func getHealthConditionStatus(health v1alpha1.Health) metav1.ConditionStatus {
+	if health == v1alpha1.HealthHealthy {
+		return metav1.ConditionTrue
+	}
+	return metav1.ConditionFalse
+}
+
+func getStateFromHealthResult(health v1alpha1.Health) v1alpha1.State {
+	switch health {
+	case v1alpha1.HealthHealthy:
+		return v1alpha1.StateReady
+	case v1alpha1.HealthDegraded:
+		return v1alpha1.StateWarning
+	default:
+		return v1alpha1.StateProcessing
+	}
+}
+
+// getAdoptedInventory returns the objects adopted by the Sample through the ThirdParties referencing it.
+// Without the ThirdParty CRD installed, no objects are adopted.
+func getAdoptedInventory(ctx context.Context, reader client.Reader,
+	objectInstance *v1alpha1.Sample,
+) ([]v1alpha1.InventoryEntry, error) {
+	thirdParties := &v1alpha1.ThirdPartyList{}
+	if err := reader.List(ctx, thirdParties, client.InNamespace(objectInstance.GetNamespace())); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error while listing thirdparties: %w", err)
+	}
+
+	adopted := make([]v1alpha1.InventoryEntry, 0)
+	// objects selected by several ThirdParties are only listed once
+	keys := map[inventoryKey]struct{}{}
+	for _, thirdParty := range thirdParties.Items {
+		if !isAdopting(&thirdParty, objectInstance) {
+			continue
+		}
+		for _, resource := range thirdParty.Status.Resources {
+			key := getInventoryKey(resource.InventoryEntry)
+			if _, found := keys[key]; found || resource.LastError != "" {
+				continue
+			}
+			keys[key] = struct{}{}
+			adopted = append(adopted, resource.InventoryEntry)
+		}
+	}
+	return adopted, nil
+}
+
+// isAdopting reports whether the ThirdParty adopts its selected objects into the inventory of the Sample.
+func isAdopting(thirdParty *v1alpha1.ThirdParty, objectInstance *v1alpha1.Sample) bool {
+	return (thirdParty.Spec.Mode == v1alpha1.AdoptionModeAdopt ||
+		thirdParty.Spec.Mode == v1alpha1.AdoptionModeTakeOwnership) &&
+		thirdParty.Spec.SampleRef != nil && thirdParty.Spec.SampleRef.Name == objectInstance.GetName() &&
+		thirdParty.GetDeletionTimestamp().IsZero()
+}
@@ -0,0 +1,128 @@
+package controllers_test
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ThirdParty CR selects objects of a legacy tool", Ordered, func() {
+	sampleCR := createSampleCR("thirdparty-sample", "")
+	sampleCRKey := client.ObjectKeyFromObject(sampleCR)
+	legacyConfig := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "legacy-config", Namespace: metav1.NamespaceDefault, Labels: map[string]string{"app": "legacy"},
+		},
+		Data: map[string]string{"key": "value"},
+	}
+	legacyConfigKey := client.ObjectKeyFromObject(legacyConfig)
+	selectedConfig := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "legacy-selected-config", Namespace: metav1.NamespaceDefault, Labels: map[string]string{"app": "legacy"},
+		},
+	}
+	selectedConfigKey := client.ObjectKeyFromObject(selectedConfig)
+	thirdPartyCR := &v1alpha1.ThirdParty{
+		ObjectMeta: metav1.ObjectMeta{Name: "legacy", Namespace: metav1.NamespaceDefault},
+		Spec: v1alpha1.ThirdPartySpec{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+			Selector:   metav1.LabelSelector{MatchLabels: map[string]string{"app": "legacy"}},
+			Mode:       v1alpha1.AdoptionModeObserve,
+		},
+	}
+	thirdPartyCRKey := client.ObjectKeyFromObject(thirdPartyCR)
+
+	BeforeAll(func() {
+		sampleCR.Spec.ResourceFilePath = createConfigMapManifest("thirdparty-sample-config")
+	})
+
+	It("should observe the health of the selected objects without managing them", func() {
+		Expect(k8sClient.Create(ctx, legacyConfig, client.FieldOwner("legacy-tool"))).To(Succeed())
+		Expect(k8sClient.Create(ctx, sampleCR)).To(Succeed())
+		Expect(k8sClient.Create(ctx, thirdPartyCR)).To(Succeed())
+
+		Eventually(getThirdPartyStatus(thirdPartyCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(And(
+				HaveField("State", v1alpha1.StateReady),
+				HaveField("Resources", ConsistOf(And(
+					HaveField("Name", legacyConfig.GetName()),
+					HaveField("Health", v1alpha1.HealthHealthy),
+				))),
+			))
+		Expect(k8sClient.Get(ctx, legacyConfigKey, legacyConfig)).To(Succeed())
+		Expect(legacyConfig.GetManagedFields()).To(ContainElement(HaveField("Manager", "legacy-tool")))
+	})
+
+	It("should observe objects selected after the ThirdParty CR was reconciled", func() {
+		Expect(k8sClient.Create(ctx, selectedConfig, client.FieldOwner("legacy-tool"))).To(Succeed())
+
+		Eventually(getThirdPartyStatus(thirdPartyCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(HaveField("Resources", ConsistOf(
+				HaveField("Name", legacyConfig.GetName()),
+				HaveField("Name", selectedConfig.GetName()),
+			)))
+	})
+
+	It("should take over the ownership of the selected objects", func() {
+		Expect(k8sClient.Get(ctx, thirdPartyCRKey, thirdPartyCR)).To(Succeed())
+		thirdPartyCR.Spec.Mode = v1alpha1.AdoptionModeTakeOwnership
+		thirdPartyCR.Spec.SampleRef = &v1alpha1.SampleReference{Name: sampleCR.GetName()}
+		Expect(k8sClient.Update(ctx, thirdPartyCR)).To(Succeed())
+
+		Eventually(func(g Gomega) []metav1.ManagedFieldsEntry {
+			g.Expect(k8sClient.Get(ctx, legacyConfigKey, legacyConfig)).To(Succeed())
+			return legacyConfig.GetManagedFields()
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			ShouldNot(ContainElement(HaveField("Manager", "legacy-tool")))
+		Eventually(getThirdPartyStatus(thirdPartyCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(HaveField("State", v1alpha1.StateReady))
+	})
+
+	It("should adopt the selected objects into the inventory of the Sample CR", func() {
+		Eventually(func(g Gomega) []v1alpha1.InventoryEntry {
+			g.Expect(k8sClient.Get(ctx, sampleCRKey, sampleCR)).To(Succeed())
+			return sampleCR.Status.AdoptedInventory
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(ConsistOf(HaveField("Name", legacyConfig.GetName()), HaveField("Name", selectedConfig.GetName())))
+	})
+
+	It("should delete the adopted objects with the SampleCR", func() {
+		Expect(k8sClient.Delete(ctx, sampleCR)).To(Succeed())
+		Eventually(func() bool {
+			return errors.IsNotFound(k8sClient.Get(ctx, legacyConfigKey, &v1.ConfigMap{})) &&
+				errors.IsNotFound(k8sClient.Get(ctx, selectedConfigKey, &v1.ConfigMap{})) &&
+				errors.IsNotFound(k8sClient.Get(ctx, sampleCRKey, &v1alpha1.Sample{}))
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeTrue())
+		Expect(k8sClient.Delete(ctx, thirdPartyCR)).To(Succeed())
+	})
+})
+
+func getThirdPartyStatus(thirdPartyObjKey client.ObjectKey) func(g Gomega) v1alpha1.ThirdPartyStatus {
+	return func(gomega Gomega) v1alpha1.ThirdPartyStatus {
+		thirdPartyCR := &v1alpha1.ThirdParty{}
+		gomega.Expect(k8sClient.Get(ctx, thirdPartyObjKey, thirdPartyCR)).To(Succeed())
+		return thirdPartyCR.Status
+	}
+}
@@ -0,0 +1,209 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+)
+
+// TracingExporter is the exporter the spans of the reconcilers are sent to.
+type TracingExporter string
+
+const (
+	// TracingExporterNone disables tracing.
+	TracingExporterNone TracingExporter = ""
+	// TracingExporterOTLP sends the spans to an OpenTelemetry collector over OTLP/gRPC.
+	TracingExporterOTLP TracingExporter = "otlp"
+	// TracingExporterStdout writes the spans as JSON to stdout or a file, for local debugging.
+	TracingExporterStdout TracingExporter = "stdout"
+
+	tracerName = "github.com/kyma-project/template-operator/controllers"
+
+	resultSuccess = "success"
+	resultError   = "error"
+
+	tracingFileMode = 0o600
+)
+
+const (
+	attributeSampleName      = attribute.Key("sample.name")
+	attributeSampleNamespace = attribute.Key("sample.namespace")
+	attributeSampleState     = attribute.Key("sample.state")
+	attributeGroup           = attribute.Key("k8s.group")
+	attributeVersion         = attribute.Key("k8s.version")
+	attributeKind            = attribute.Key("k8s.kind")
+	attributeName            = attribute.Key("k8s.name")
+	attributeNamespace       = attribute.Key("k8s.namespace")
+	attributeManifestSource  = attribute.Key("manifest.source")
+	attributeManifestObjects = attribute.Key("manifest.objects")
+	attributeRequeueAfter    = attribute.Key("reconcile.requeue_after")
+	attributeResult          = attribute.Key("result")
+)
+
+var (
+	errUnknownTracingExporter = errors.New("unknown tracing exporter, expected otlp or stdout")
+	errInvalidSamplingRatio   = errors.New("invalid sampling ratio, expected a value between 0 and 1")
+)
+
+// TracingOptions configure the export of the spans of the reconcilers.
+type TracingOptions struct {
+	Exporter TracingExporter
+	// Endpoint of the OTLP collector as host:port, OTEL_EXPORTER_OTLP_ENDPOINT is used if empty
+	Endpoint string
+	// Insecure sends the spans to the OTLP collector without TLS
+	Insecure bool
+	// SamplingRatio of the traces started by the reconcilers, traces of callers keep their sampling decision
+	SamplingRatio float64
+	// File the stdout exporter appends the spans to, stdout is used if empty
+	File string
+	// ServiceName is recorded as service.name of all spans
+	ServiceName string
+}
+
+// SetupTracing registers a tracer provider exporting the spans of the reconcilers as configured by the options
+// and returns the function flushing the pending spans on shutdown. Without an exporter, tracing stays disabled.
+func SetupTracing(ctx context.Context, opts TracingOptions) (func(context.Context) error, error) {
+	if opts.Exporter == TracingExporterNone {
+		return func(context.Context) error { return nil }, nil
+	}
+	if opts.SamplingRatio < 0 || opts.SamplingRatio > 1 {
+		return nil, fmt.Errorf("%w: %v", errInvalidSamplingRatio, opts.SamplingRatio)
+	}
+
+	exporter, closer, err := newSpanExporter(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SamplingRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", opts.ServiceName))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		ctrl.Log.WithName("tracing").Error(err, "error while exporting spans")
+	}))
+
+	return func(ctx context.Context) error {
+		return errors.Join(provider.Shutdown(ctx), closer.Close())
+	}, nil
+}
+
+func newSpanExporter(ctx context.Context, opts TracingOptions) (sdktrace.SpanExporter, io.Closer, error) {
+	switch opts.Exporter {
+	case TracingExporterOTLP:
+		grpcOpts := make([]otlptracegrpc.Option, 0)
+		if opts.Endpoint != "" {
+			grpcOpts = append(grpcOpts, otlptracegrpc.WithEndpoint(opts.Endpoint))
+		}
+		if opts.Insecure {
+			grpcOpts = append(grpcOpts, otlptracegrpc.WithInsecure())
+		}
+		exporter, err := otlptracegrpc.New(ctx, grpcOpts...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error while creating OTLP exporter: %w", err)
+		}
+		return exporter, nopWriteCloser{}, nil
+	case TracingExporterStdout:
+		var writer io.WriteCloser = nopWriteCloser{Writer: os.Stdout}
+		if opts.File != "" {
+			file, err := os.OpenFile(opts.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, tracingFileMode)
+			if err != nil {
+				return nil, nil, fmt.Errorf("error while opening tracing file: %w", err)
+			}
+			writer = file
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(writer))
+		if err != nil {
+			return nil, nil, fmt.Errorf("error while creating stdout exporter: %w", err)
+		}
+		return exporter, writer, nil
+	default:
+		return nil, nil, fmt.Errorf("%w: %s", errUnknownTracingExporter, opts.Exporter)
+	}
+}
+
+// nopWriteCloser keeps stdout open when the tracer provider is shut down.
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// startSpan starts a span of the operation with the attributes, as a child of the span of the context.
+//
+//nolint:ireturn // spans are only available through the trace.Span interface
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan records the result of the operation of the span and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.SetAttributes(attributeResult.String(resultError))
+	} else {
+		span.SetAttributes(attributeResult.String(resultSuccess))
+	}
+	span.End()
+}
+
+// sampleAttributes identify the Sample and its current State in a span.
+func sampleAttributes(objectInstance *v1alpha1.Sample) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attributeSampleName.String(objectInstance.GetName()),
+		attributeSampleNamespace.String(objectInstance.GetNamespace()),
+		attributeSampleState.String(string(objectInstance.Status.State)),
+	}
+}
+
+// objectAttributes identify the object of an API call in a span.
+// The kind of typed objects, which usually have no TypeMeta, is looked up in the scheme of the client.
+func objectAttributes(c client.Client, obj client.Object) []attribute.KeyValue {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	if gvk.Empty() {
+		// the kind stays empty in the span, if the object is not known to the scheme
+		gvk, _ = c.GroupVersionKindFor(obj)
+	}
+	return []attribute.KeyValue{
+		attributeGroup.String(gvk.Group),
+		attributeVersion.String(gvk.Version),
+		attributeKind.String(gvk.Kind),
+		attributeName.String(obj.GetName()),
+		attributeNamespace.String(obj.GetNamespace()),
+	}
+}
+
+// traceState runs the handler of the current State of the Sample in a span named after the handler.
+func traceState(ctx context.Context, name string, objectInstance *v1alpha1.Sample,
+	handler func(context.Context, *v1alpha1.Sample) error,
+) error {
+	ctx, span := startSpan(ctx, name, sampleAttributes(objectInstance)...)
+	err := handler(ctx, objectInstance)
+	endSpan(span, err)
+	return err
+}
+
+// deleteObject deletes the object in a span. Objects which no longer exist are not recorded as an error.
+func deleteObject(ctx context.Context, c client.Client, obj client.Object) error {
+	ctx, span := startSpan(ctx, "Delete", objectAttributes(c, obj)...)
+	err := c.Delete(ctx, obj)
+	endSpan(span, client.IgnoreNotFound(err))
+	return err
+}
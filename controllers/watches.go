@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+)
+
+const (
+	// labelSampleName and labelSampleNamespace are set on all applied objects,
+	// so that their events can be mapped back to the Sample they belong to.
+	labelSampleName      = "sample.kyma-project.io/name"
+	labelSampleNamespace = "sample.kyma-project.io/namespace"
+)
+
+// resourceWatcher sets up watches on kinds only known at runtime, e.g. the kinds of the applied objects
+// once they are applied for the first time. Only the metadata of the watched objects is cached.
+type resourceWatcher struct {
+	controller controller.Controller
+	cache      cache.Cache
+	// newHandler returns the handler mapping the events of the objects of a kind to reconciliations
+	newHandler func(schema.GroupVersionKind) handler.EventHandler
+	predicates []predicate.Predicate
+
+	mu      sync.Mutex
+	watched map[schema.GroupVersionKind]struct{}
+}
+
+func newResourceWatcher(ctrl controller.Controller, cache cache.Cache,
+	newHandler func(schema.GroupVersionKind) handler.EventHandler, predicates ...predicate.Predicate,
+) *resourceWatcher {
+	return &resourceWatcher{
+		controller: ctrl,
+		cache:      cache,
+		newHandler: newHandler,
+		predicates: predicates,
+		watched:    map[schema.GroupVersionKind]struct{}{},
+	}
+}
+
+// newSampleHandler maps the events of applied objects to the Sample they belong to, whatever their kind.
+//
+//nolint:ireturn // handlers are only available through the handler.EventHandler interface
+func newSampleHandler(schema.GroupVersionKind) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(getSampleRequests)
+}
+
+// watchResources ensures that the kinds of all objects are watched.
+func (w *resourceWatcher) watchResources(resources []*unstructured.Unstructured) error {
+	for _, obj := range resources {
+		if err := w.watch(obj.GroupVersionKind()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *resourceWatcher) watch(gvk schema.GroupVersionKind) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, found := w.watched[gvk]; found {
+		return nil
+	}
+
+	obj := &metav1.PartialObjectMetadata{}
+	obj.SetGroupVersionKind(gvk)
+	src := source.Kind[client.Object](w.cache, obj, w.newHandler(gvk), w.predicates...)
+	if err := w.controller.Watch(src); err != nil {
+		return fmt.Errorf("error while watching %s: %w", gvk, err)
+	}
+	w.watched[gvk] = struct{}{}
+	return nil
+}
+
+// setSampleLabels labels the objects with the Sample they belong to.
+func setSampleLabels(resources []*unstructured.Unstructured, objectInstance *v1alpha1.Sample) {
+	for _, obj := range resources {
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = make(map[string]string, 2)
+		}
+		labels[labelSampleName] = objectInstance.GetName()
+		labels[labelSampleNamespace] = objectInstance.GetNamespace()
+		obj.SetLabels(labels)
+	}
+}
+
+func hasSampleLabels(obj client.Object) bool {
+	_, found := obj.GetLabels()[labelSampleName]
+	return found
+}
+
+// getSampleRequests maps an event of an applied object to a reconciliation of the Sample it belongs to.
+func getSampleRequests(_ context.Context, obj client.Object) []reconcile.Request {
+	labels := obj.GetLabels()
+	name, found := labels[labelSampleName]
+	if !found {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{
+		Namespace: labels[labelSampleNamespace],
+		Name:      name,
+	}}}
+}
@@ -0,0 +1,78 @@
+package controllers_test
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/template-operator/api/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sample CR watches its applied resources", Ordered, func() {
+	sampleCR := createSampleCR("drift-sample", "")
+	sampleCRKey := client.ObjectKeyFromObject(sampleCR)
+	configKey := client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "drift-config"}
+
+	BeforeAll(func() {
+		sampleCR.Spec.ResourceFilePath = createConfigMapManifest(configKey.Name)
+	})
+
+	It("should label the applied resources with the SampleCR", func() {
+		Expect(k8sClient.Create(ctx, sampleCR)).To(Succeed())
+
+		Eventually(getCRStatus(sampleCRKey)).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Equal(CRStatus{State: v1alpha1.StateReady, InstallConditionStatus: metav1.ConditionTrue, Err: nil}))
+
+		configMap := &v1.ConfigMap{}
+		Expect(k8sClient.Get(ctx, configKey, configMap)).To(Succeed())
+		Expect(configMap.GetLabels()).To(HaveKeyWithValue("sample.kyma-project.io/name", sampleCR.GetName()))
+		Expect(configMap.GetLabels()).To(HaveKeyWithValue("sample.kyma-project.io/namespace", sampleCR.GetNamespace()))
+	})
+
+	It("should revert changes of the applied resources without waiting for the resync", func() {
+		configMap := &v1.ConfigMap{}
+		Expect(k8sClient.Get(ctx, configKey, configMap)).To(Succeed())
+		configMap.Data["key"] = "drifted"
+		Expect(k8sClient.Update(ctx, configMap)).To(Succeed())
+
+		Eventually(func(g Gomega) string {
+			g.Expect(k8sClient.Get(ctx, configKey, configMap)).To(Succeed())
+			return configMap.Data["key"]
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Equal("value"))
+	})
+
+	It("should recreate deleted resources without waiting for the resync", func() {
+		Expect(k8sClient.Delete(ctx, &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Namespace: configKey.Namespace, Name: configKey.Name,
+		}})).To(Succeed())
+
+		Eventually(func() error {
+			return k8sClient.Get(ctx, configKey, &v1.ConfigMap{})
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(Succeed())
+	})
+
+	It("should delete the resources with the SampleCR", func() {
+		Expect(k8sClient.Delete(ctx, sampleCR)).To(Succeed())
+		Eventually(func() bool {
+			return errors.IsNotFound(k8sClient.Get(ctx, configKey, &v1.ConfigMap{})) &&
+				errors.IsNotFound(k8sClient.Get(ctx, sampleCRKey, &v1alpha1.Sample{}))
+		}).
+			WithTimeout(30 * time.Second).
+			WithPolling(500 * time.Millisecond).
+			Should(BeTrue())
+	})
+})
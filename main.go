@@ -17,15 +17,20 @@ limitations under the License.
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/go-logr/logr"
 	machineryruntime "k8s.io/apimachinery/pkg/runtime"
 	machineryutilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
@@ -40,27 +45,49 @@ import (
 )
 
 const (
-	rateLimiterBurstDefault     = 200
-	rateLimiterFrequencyDefault = 30
-	failureBaseDelayDefault     = 1 * time.Second
-	failureMaxDelayDefault      = 1000 * time.Second
-	operatorName                = "template-operator"
-	webhookPort                 = 9443
+	rateLimiterBurstDefault        = 200
+	rateLimiterFrequencyDefault    = 30
+	failureBaseDelayDefault        = 1 * time.Second
+	failureMaxDelayDefault         = 1000 * time.Second
+	healthCheckTimeoutDefault      = 5 * time.Minute
+	resyncPeriodDefault            = 10 * time.Minute
+	maxStatusResourcesDefault      = 100
+	operatorName                   = "template-operator"
+	webhookPort                    = 9443
+	defaultResourceFilePathDefault = "./module-data/yaml"
+	tracingSamplingRatioDefault    = 1.0
+	tracingShutdownTimeout         = 5 * time.Second
 )
 
 type FlagVar struct {
-	metricsAddr          string
-	enableLeaderElection bool
-	probeAddr            string
-	failureBaseDelay     time.Duration
-	failureMaxDelay      time.Duration
-	rateLimiterFrequency int
-	rateLimiterBurst     int
-	finalState           string
-	finalDeletionState   string
-	printVersion         bool
+	metricsAddr             string
+	enableLeaderElection    bool
+	probeAddr               string
+	failureBaseDelay        time.Duration
+	failureMaxDelay         time.Duration
+	rateLimiterFrequency    int
+	rateLimiterBurst        int
+	finalState              string
+	finalDeletionState      string
+	deletionBlockedState    string
+	healthCheckTimeout      time.Duration
+	resyncPeriod            time.Duration
+	dryRun                  bool
+	maxStatusResources      int
+	defaultResourceFilePath string
+	enableWebhooks          bool
+	faultInjectionRules     string
+	faultInjectionConfigMap string
+	tracingExporter         string
+	tracingOTLPEndpoint     string
+	tracingOTLPInsecure     bool
+	tracingSamplingRatio    float64
+	tracingFile             string
+	printVersion            bool
 }
 
+var errInvalidConfigMapKey = errors.New("invalid ConfigMap key, expected <namespace>/<name>")
+
 func registerSchemes(scheme *machineryruntime.Scheme) {
 	machineryutilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	machineryutilruntime.Must(controllers.AddToScheme(scheme))
@@ -117,16 +144,67 @@ func main() {
 		os.Exit(1)
 	}
 
+	ctx := ctrl.SetupSignalHandler()
+
+	shutdownTracing, err := controllers.SetupTracing(ctx, controllers.TracingOptions{
+		Exporter:      controllers.TracingExporter(flagVar.tracingExporter),
+		Endpoint:      flagVar.tracingOTLPEndpoint,
+		Insecure:      flagVar.tracingOTLPInsecure,
+		SamplingRatio: flagVar.tracingSamplingRatio,
+		File:          flagVar.tracingFile,
+		ServiceName:   operatorName,
+	})
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+
+	sampleClient, err := getSampleClient(mgr.GetClient(), mgr.GetAPIReader(), flagVar)
+	if err != nil {
+		setupLog.Error(err, "unable to set up fault injection")
+		os.Exit(1)
+	}
 	if err = (&controllers.SampleReconciler{
-		Client:             mgr.GetClient(),
-		Scheme:             mgr.GetScheme(),
-		EventRecorder:      mgr.GetEventRecorder(operatorName),
-		FinalState:         v1alpha1.State(flagVar.finalState),
-		FinalDeletionState: v1alpha1.State(flagVar.finalDeletionState),
+		Client:               sampleClient,
+		Scheme:               mgr.GetScheme(),
+		EventRecorder:        mgr.GetEventRecorder(operatorName),
+		FinalState:           v1alpha1.State(flagVar.finalState),
+		FinalDeletionState:   v1alpha1.State(flagVar.finalDeletionState),
+		HealthCheckTimeout:   flagVar.healthCheckTimeout,
+		ResyncPeriod:         flagVar.resyncPeriod,
+		DryRun:               flagVar.dryRun,
+		MaxStatusResources:   flagVar.maxStatusResources,
+		DeletionBlockedState: v1alpha1.State(flagVar.deletionBlockedState),
 	}).SetupWithManager(mgr, rateLimiter); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Sample")
 		os.Exit(1)
 	}
+	if err = (&controllers.ManagedReconciler{
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		EventRecorder: mgr.GetEventRecorder(operatorName),
+		ResyncPeriod:  flagVar.resyncPeriod,
+	}).SetupWithManager(ctx, mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Managed")
+		os.Exit(1)
+	}
+	if err = (&controllers.ThirdPartyReconciler{
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		EventRecorder: mgr.GetEventRecorder(operatorName),
+		ResyncPeriod:  flagVar.resyncPeriod,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ThirdParty")
+		os.Exit(1)
+	}
+	if flagVar.enableWebhooks {
+		if err = (&controllers.SampleWebhook{
+			DefaultResourceFilePath: flagVar.defaultResourceFilePath,
+		}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Sample")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -139,12 +217,47 @@ func main() {
 	}
 
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	err = mgr.Start(ctx)
+	flushSpans(shutdownTracing, setupLog)
+	if err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
 
+// flushSpans exports the pending spans once the manager stopped.
+// The context of the manager is done by then, so a fresh one limits the time it takes.
+func flushSpans(shutdownTracing func(context.Context) error, setupLog logr.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+	defer cancel()
+	if err := shutdownTracing(ctx); err != nil {
+		setupLog.Error(err, "problem flushing spans")
+	}
+}
+
+// getSampleClient returns the client of the Sample reconciler, which injects faults into its calls
+// if fault injection rules or a ConfigMap holding them are configured.
+//
+//nolint:ireturn // the fault injecting client is only used if configured
+func getSampleClient(c client.Client, reader client.Reader, flagVar *FlagVar) (client.Client, error) {
+	if flagVar.faultInjectionRules == "" && flagVar.faultInjectionConfigMap == "" {
+		return c, nil
+	}
+	rules, err := controllers.ParseFaultRules(flagVar.faultInjectionRules)
+	if err != nil {
+		return nil, fmt.Errorf("error in --fault-injection-rules: %w", err)
+	}
+	var configMap *client.ObjectKey
+	if flagVar.faultInjectionConfigMap != "" {
+		namespace, name, found := strings.Cut(flagVar.faultInjectionConfigMap, "/")
+		if !found || namespace == "" || name == "" {
+			return nil, fmt.Errorf("%w: %s", errInvalidConfigMapKey, flagVar.faultInjectionConfigMap)
+		}
+		configMap = &client.ObjectKey{Namespace: namespace, Name: name}
+	}
+	return controllers.NewFaultInjectingClient(c, reader, rules, configMap), nil
+}
+
 func defineFlagVar() *FlagVar {
 	flagVar := new(FlagVar)
 	flag.StringVar(&flagVar.metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
@@ -164,6 +277,37 @@ func defineFlagVar() *FlagVar {
 		"Customize final state, to mimic state behaviour like Ready, Warning")
 	flag.StringVar(&flagVar.finalDeletionState, "final-deletion-state", string(v1alpha1.StateDeleting),
 		"Customize final state when module marked for deletion, to mimic state behaviour like Ready, Warning")
+	flag.StringVar(&flagVar.deletionBlockedState, "deletion-blocked-state", string(v1alpha1.StateWarning),
+		"Customize state while the deletion of a module is blocked by custom resources of its CRDs")
+	flag.DurationVar(&flagVar.healthCheckTimeout, "health-check-timeout", healthCheckTimeoutDefault,
+		"Indicates the duration after which resources which are not healthy set the Sample CR to Error state")
+	flag.DurationVar(&flagVar.resyncPeriod, "resync-period", resyncPeriodDefault,
+		"Indicates the period after which a Ready Sample CR is reconciled, if none of its resources changed")
+	flag.BoolVar(&flagVar.dryRun, "dry-run", false,
+		"Plans the changes of all Sample CRs and records them in their status, without applying them")
+	flag.IntVar(&flagVar.maxStatusResources, "max-status-resources", maxStatusResourcesDefault,
+		"Indicates the maximum number of resources listed in the status of a Sample CR")
+	flag.StringVar(&flagVar.defaultResourceFilePath, "default-resource-file-path", defaultResourceFilePathDefault,
+		"Indicates the resourceFilePath set on Sample CRs without any manifest source")
+	flag.BoolVar(&flagVar.enableWebhooks, "enable-webhooks", false,
+		"Serves the conversion, defaulting and validating webhooks of Sample CRs, which requires a serving certificate")
+	flag.StringVar(&flagVar.faultInjectionRules, "fault-injection-rules", "",
+		"Injects faults into the calls of the Sample reconciler to the API server, given as a YAML or JSON list "+
+			"of rules, e.g. [{fault: Conflict, verbs: [apply], kind: Deployment, group: apps, probability: 0.5}]")
+	flag.StringVar(&flagVar.faultInjectionConfigMap, "fault-injection-configmap", "",
+		"Indicates a ConfigMap as <namespace>/<name> holding further fault injection rules in its \"rules\" key, "+
+			"which are read again every 5 seconds")
+	flag.StringVar(&flagVar.tracingExporter, "tracing-exporter", "",
+		"Exports the spans of the reconcilers to an OTLP/gRPC collector with \"otlp\", or as JSON with \"stdout\". "+
+			"Tracing is disabled if empty")
+	flag.StringVar(&flagVar.tracingOTLPEndpoint, "tracing-otlp-endpoint", "",
+		"Indicates the host:port of the OTLP/gRPC collector, OTEL_EXPORTER_OTLP_ENDPOINT is used if empty")
+	flag.BoolVar(&flagVar.tracingOTLPInsecure, "tracing-otlp-insecure", false,
+		"Sends the spans to the OTLP/gRPC collector without TLS")
+	flag.Float64Var(&flagVar.tracingSamplingRatio, "tracing-sampling-ratio", tracingSamplingRatioDefault,
+		"Indicates the ratio between 0 and 1 of the sampled reconciliations")
+	flag.StringVar(&flagVar.tracingFile, "tracing-file", "",
+		"Indicates the file the stdout tracing exporter appends the spans to, instead of stdout")
 	flag.BoolVar(&flagVar.printVersion, "version", false, "Prints the operator version and exits")
 	return flagVar
 }